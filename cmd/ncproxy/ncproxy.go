@@ -539,7 +539,7 @@ func (s *grpcService) DeleteNetwork(ctx context.Context, req *ncproxygrpc.Delete
 	}
 
 	if _, err := s.ncpNetworkingStore.GetNetworkByName(ctx, req.Name); err == nil {
-		if err := s.ncpNetworkingStore.DeleteNetwork(ctx, req.Name); err != nil {
+		if err := s.ncpNetworkingStore.DeleteNetwork(ctx, req.Name, false); err != nil {
 			return nil, errors.Wrapf(err, "failed to delete network with name %q", req.Name)
 		}
 	} else {
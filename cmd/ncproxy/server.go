@@ -7,6 +7,7 @@ import (
 	"net"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Microsoft/go-winio"
 	"github.com/Microsoft/hcsshim/internal/log"
@@ -47,6 +48,7 @@ func newServer(ctx context.Context, conf *config, dbPath string) (*server, error
 	agentStore := ncproxystore.NewComputeAgentStore(db)
 	agentCache := newComputeAgentCache()
 	reconnectComputeAgents(ctx, agentStore, agentCache)
+	go monitorComputeAgents(ctx, agentStore, agentCache, computeAgentHealthInterval)
 
 	ttrpcServer, err := ttrpc.NewServer(ttrpc.WithUnaryServerInterceptor(octtrpc.ServerInterceptor()))
 	if err != nil {
@@ -200,6 +202,91 @@ func reconnectComputeAgents(ctx context.Context, agentStore *ncproxystore.Comput
 	wg.Wait()
 }
 
+// computeAgentHealthInterval is how often monitorComputeAgents probes the
+// registered compute agent addresses.
+const computeAgentHealthInterval = 30 * time.Second
+
+// monitorComputeAgents periodically health-checks the registered compute
+// agent addresses so that AddNIC/DeleteNIC calls don't discover dead agents
+// at request time. Each probe dials the agent's named pipe; a few failed
+// attempts with backoff evict the client from the cache, while an address
+// that answers but has no cached client (for example after a dropped
+// connection) is reconnected. The registration in the database is kept
+// either way: an eviction may be caused by a transient outage such as an
+// agent restart, and the registration is what allows a later probe (or a
+// ncproxy restart) to reconnect. Registrations are only removed by an
+// explicit unregister. Runs until `ctx` is cancelled.
+func monitorComputeAgents(ctx context.Context, agentStore *ncproxystore.ComputeAgentStore, agentCache *computeAgentCache, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		computeAgentMap, err := agentStore.GetComputeAgents(ctx)
+		if err != nil {
+			if !errors.Is(err, ncproxystore.ErrBucketNotFound) {
+				log.G(ctx).WithError(err).Error("failed to get compute agent information for health check")
+			}
+			continue
+		}
+		// Probe the agents in parallel so the retry backoff on one dead
+		// agent doesn't delay the health checks of the others.
+		var wg sync.WaitGroup
+		for cid, addr := range computeAgentMap {
+			wg.Add(1)
+			go func(containerID, agentAddress string) {
+				defer wg.Done()
+				healthCheckComputeAgent(ctx, agentCache, containerID, agentAddress)
+			}(cid, addr)
+		}
+		wg.Wait()
+	}
+}
+
+func healthCheckComputeAgent(ctx context.Context, agentCache *computeAgentCache, containerID, agentAddress string) {
+	entry := log.G(ctx).WithFields(logrus.Fields{
+		"containerID":  containerID,
+		"agentAddress": agentAddress,
+	})
+	var err error
+	for i, backoff := 0, time.Second; i < 3; i, backoff = i+1, backoff*2 {
+		if i != 0 {
+			time.Sleep(backoff)
+		}
+		var conn net.Conn
+		if conn, err = winioDialPipe(agentAddress, nil); err == nil {
+			conn.Close()
+			break
+		}
+	}
+	if err != nil {
+		// The agent is unreachable; evict the client so requests fail fast
+		// instead of dialing a dead address. The store registration is kept
+		// so the agent is reconnected if it comes back.
+		entry.WithError(err).Warn("compute agent failed health check, evicting client")
+		if agent, err := agentCache.getAndDelete(containerID); err == nil && agent != nil {
+			if err := agent.Close(); err != nil {
+				entry.WithError(err).Warn("failed to close evicted compute agent client")
+			}
+		}
+		return
+	}
+	if agent, err := agentCache.get(containerID); err != nil || agent != nil {
+		return
+	}
+	// The address answers but we have no client for it; reconnect.
+	service, err := getComputeAgentClient(agentAddress)
+	if err != nil {
+		entry.WithError(err).Warn("failed to reconnect to compute agent")
+		return
+	}
+	entry.Info("reconnected to container's compute agent")
+	_ = agentCache.put(containerID, service)
+}
+
 // disconnectComputeAgents clears the cache of compute agent clients and cleans up
 // their resources.
 func disconnectComputeAgents(ctx context.Context, containerIDToComputeAgent *computeAgentCache) error {
@@ -23,6 +23,14 @@ import (
 
 var errIOTimeOut = errors.New("timed out waiting for stdio relay")
 
+// stdioDrainer is implemented by process hosts (currently *hcs.Process) that
+// can hold the stdio pipes open after process exit until registered relays
+// have consumed any buffered output.
+type stdioDrainer interface {
+	SetStdioDrainTimeout(timeout time.Duration)
+	RegisterStdioRelay() (done func())
+}
+
 // CmdProcessRequest stores information on command requests made through this package.
 type CmdProcessRequest struct {
 	Args     []string
@@ -52,6 +60,18 @@ type Cmd struct {
 	// Context provides a context that terminates the process when it is done.
 	Context context.Context
 
+	// PriorityClass is the optional priority class to assign to the process
+	// on Windows hosts (Idle, BelowNormal, Normal, AboveNormal or High), so
+	// that housekeeping execs don't compete with the primary workload.
+	PriorityClass string
+
+	// SensitiveEnvironment marks the process environment as sensitive so it
+	// is omitted from logged copies of the process document, regardless of
+	// the global scrubbing setting. Use this when the environment carries
+	// secrets or tokens. The marker is applied to the logging context only
+	// and is never part of the document sent to the platform.
+	SensitiveEnvironment bool
+
 	// CopyAfterExitTimeout is the amount of time after process exit we allow the
 	// stdout, stderr relays to continue before forcibly closing them if not
 	// already completed. This is primarily a safety step against the HCS when
@@ -93,6 +113,26 @@ func (err *ExitError) Error() string {
 	return fmt.Sprintf("process exited with exit code %d", err.ExitCode())
 }
 
+// Valid process priority classes for Cmd.PriorityClass on Windows hosts.
+const (
+	PriorityClassIdle        = "Idle"
+	PriorityClassBelowNormal = "BelowNormal"
+	PriorityClassNormal      = "Normal"
+	PriorityClassAboveNormal = "AboveNormal"
+	PriorityClassHigh        = "High"
+)
+
+// validatePriorityClass validates a priority class value for a WCOW process
+// document.
+func validatePriorityClass(priorityClass string) error {
+	switch priorityClass {
+	case PriorityClassIdle, PriorityClassBelowNormal, PriorityClassNormal, PriorityClassAboveNormal, PriorityClassHigh:
+		return nil
+	default:
+		return fmt.Errorf("invalid process priority class: %s", priorityClass)
+	}
+}
+
 // Additional fields to hcsschema.ProcessParameters used by LCOW.
 type lcowProcessParameters struct {
 	hcsschema.ProcessParameters
@@ -185,6 +225,16 @@ func (c *Cmd) Start() error {
 				int32(c.Spec.ConsoleSize.Width),
 			}
 		}
+
+		if c.PriorityClass != "" {
+			if c.Host.OS() != "windows" {
+				return fmt.Errorf("process priority class is only supported on Windows hosts")
+			}
+			if err := validatePriorityClass(c.PriorityClass); err != nil {
+				return err
+			}
+			wpp.PriorityClass = c.PriorityClass
+		}
 		x = wpp
 	} else {
 		lpp := &lcowProcessParameters{
@@ -200,7 +250,11 @@ func (c *Cmd) Start() error {
 	if c.Context != nil && c.Context.Err() != nil {
 		return c.Context.Err()
 	}
-	p, err := c.Host.CreateProcess(context.TODO(), x)
+	ctx := context.TODO()
+	if c.SensitiveEnvironment {
+		ctx = log.WithSensitiveEnvironment(ctx)
+	}
+	p, err := c.Host.CreateProcess(ctx, x)
 	if err != nil {
 		return err
 	}
@@ -209,6 +263,15 @@ func (c *Cmd) Start() error {
 		c.Log = c.Log.WithField("pid", p.Pid())
 	}
 
+	// Register the stdout/stderr relays with the process so that, when
+	// supported, the exit notification waits for them to drain buffered
+	// output before the pipes are broken.
+	registerRelay := func() func() { return nil }
+	if d, ok := p.(stdioDrainer); ok && c.CopyAfterExitTimeout != 0 && (c.Stdout != nil || c.Stderr != nil) {
+		d.SetStdioDrainTimeout(c.CopyAfterExitTimeout)
+		registerRelay = d.RegisterStdioRelay
+	}
+
 	// Start relaying process IO.
 	stdin, stdout, stderr := p.Stdio()
 	if c.Stdin != nil {
@@ -231,8 +294,12 @@ func (c *Cmd) Start() error {
 	}
 
 	if c.Stdout != nil {
+		done := registerRelay()
 		c.ioGrp.Go(func() error {
 			_, err := relayIO(c.Stdout, stdout, c.Log, "stdout")
+			if done != nil {
+				done()
+			}
 			if cErr := p.CloseStdout(context.TODO()); cErr != nil && c.Log != nil {
 				c.Log.WithError(cErr).Warn("failed to close Cmd stdout")
 			}
@@ -241,8 +308,12 @@ func (c *Cmd) Start() error {
 	}
 
 	if c.Stderr != nil {
+		done := registerRelay()
 		c.ioGrp.Go(func() error {
 			_, err := relayIO(c.Stderr, stderr, c.Log, "stderr")
+			if done != nil {
+				done()
+			}
 			if cErr := p.CloseStderr(context.TODO()); cErr != nil && c.Log != nil {
 				c.Log.WithError(cErr).Warn("failed to close Cmd stderr")
 			}
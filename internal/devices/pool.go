@@ -0,0 +1,148 @@
+//go:build windows
+
+package devices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/internal/windevice"
+)
+
+var (
+	// ErrDeviceNotInPool is returned when a location path is not part of the pool.
+	ErrDeviceNotInPool = errors.New("device is not in the pool")
+	// ErrDeviceInUse is returned when the requested device is assigned to another UVM.
+	ErrDeviceInUse = errors.New("device is already assigned")
+	// ErrPoolExhausted is returned by Allocate when every device is assigned.
+	ErrPoolExhausted = errors.New("no free devices in the pool")
+)
+
+// PoolDevice is a snapshot of one assignable device in a DevicePool.
+type PoolDevice struct {
+	// LocationPath is the PCI location path the device is assigned by.
+	LocationPath string
+	// AssignedUVM is the ID of the utility VM the device is assigned to,
+	// empty while the device is free.
+	AssignedUVM string
+}
+
+// DevicePool tracks a class of identical assignable host devices (for
+// example a set of Mellanox NICs, GPUs or FPGAs) and which utility VM each
+// is assigned to, so callers sharing the pool cannot hand the same device to
+// two VMs. Assignments are tracked in memory only; on restart rebuild the
+// pool and re-reserve the devices of still-running VMs with AllocatePath.
+type DevicePool struct {
+	mu      sync.Mutex
+	devices map[string]string // location path -> assigned UVM ID ("" while free)
+}
+
+// NewDevicePool enumerates the PCI location paths of the given host PnP
+// device instance IDs and builds a pool from them.
+func NewDevicePool(instanceIDs []string) (*DevicePool, error) {
+	locationPaths, err := windevice.GetDeviceLocationPathsFromIDs(instanceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location paths for pool devices: %w", err)
+	}
+	return NewDevicePoolFromLocationPaths(locationPaths), nil
+}
+
+// NewDevicePoolFromLocationPaths builds a pool from already-known PCI
+// location paths.
+func NewDevicePoolFromLocationPaths(locationPaths []string) *DevicePool {
+	p := &DevicePool{devices: make(map[string]string)}
+	for _, lp := range locationPaths {
+		p.devices[lp] = ""
+	}
+	return p
+}
+
+// Allocate reserves a free device for `vmID` and returns its location path,
+// or ErrPoolExhausted when every device is assigned.
+func (p *DevicePool) Allocate(vmID string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	paths := make([]string, 0, len(p.devices))
+	for lp := range p.devices {
+		paths = append(paths, lp)
+	}
+	sort.Strings(paths)
+	for _, lp := range paths {
+		if p.devices[lp] == "" {
+			p.devices[lp] = vmID
+			return lp, nil
+		}
+	}
+	return "", ErrPoolExhausted
+}
+
+// AllocatePath reserves the specific device at `locationPath` for `vmID`,
+// failing with ErrDeviceInUse if another UVM holds it. Reserving a device
+// already held by `vmID` is a no-op.
+func (p *DevicePool) AllocatePath(vmID, locationPath string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	owner, ok := p.devices[locationPath]
+	if !ok {
+		return fmt.Errorf("device %s: %w", locationPath, ErrDeviceNotInPool)
+	}
+	if owner != "" && owner != vmID {
+		return fmt.Errorf("device %s is assigned to %s: %w", locationPath, owner, ErrDeviceInUse)
+	}
+	p.devices[locationPath] = vmID
+	return nil
+}
+
+// Release returns the device at `locationPath` to the pool.
+func (p *DevicePool) Release(locationPath string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.devices[locationPath]; !ok {
+		return fmt.Errorf("device %s: %w", locationPath, ErrDeviceNotInPool)
+	}
+	p.devices[locationPath] = ""
+	return nil
+}
+
+// Assignments returns a snapshot of the pool's devices and their owners.
+func (p *DevicePool) Assignments() []PoolDevice {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	result := make([]PoolDevice, 0, len(p.devices))
+	for lp, owner := range p.devices {
+		result = append(result, PoolDevice{LocationPath: lp, AssignedUVM: owner})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].LocationPath < result[j].LocationPath })
+	return result
+}
+
+// Assign reserves a free device from the pool and attaches it to `vm`,
+// returning the reservation to the pool if the attach fails. The returned
+// location path must be passed to ReleaseFromUVM when the device is removed.
+func (p *DevicePool) Assign(ctx context.Context, vm *uvm.UtilityVM) (*uvm.VPCIDevice, string, error) {
+	locationPath, err := p.Allocate(vm.ID())
+	if err != nil {
+		return nil, "", err
+	}
+	vpci, err := vm.AssignDevice(ctx, locationPath, 0, "")
+	if err != nil {
+		_ = p.Release(locationPath)
+		return nil, "", fmt.Errorf("failed to assign pool device %s: %w", locationPath, err)
+	}
+	return vpci, locationPath, nil
+}
+
+// ReleaseFromUVM detaches a pool device from `vm` and returns it to the
+// pool. The pool reservation is released even if the detach fails, as HCS
+// releases the device when the VM is torn down.
+func (p *DevicePool) ReleaseFromUVM(ctx context.Context, vpci *uvm.VPCIDevice, locationPath string) error {
+	err := vpci.Release(ctx)
+	if rerr := p.Release(locationPath); rerr != nil && err == nil {
+		err = rerr
+	}
+	return err
+}
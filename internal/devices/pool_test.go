@@ -0,0 +1,109 @@
+//go:build windows
+
+package devices
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestPool() *DevicePool {
+	return NewDevicePoolFromLocationPaths([]string{
+		"PCIROOT(0)#PCI(0300)",
+		"PCIROOT(0)#PCI(0301)",
+		"PCIROOT(0)#PCI(0302)",
+	})
+}
+
+func TestPoolAllocateLowestFree(t *testing.T) {
+	p := newTestPool()
+
+	lp, err := p.Allocate("vm1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lp != "PCIROOT(0)#PCI(0300)" {
+		t.Fatalf("expected lowest free device, got %s", lp)
+	}
+
+	lp, err = p.Allocate("vm2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lp != "PCIROOT(0)#PCI(0301)" {
+		t.Fatalf("expected next free device, got %s", lp)
+	}
+}
+
+func TestPoolAllocateExhausted(t *testing.T) {
+	p := newTestPool()
+	for i := 0; i < 3; i++ {
+		if _, err := p.Allocate("vm1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := p.Allocate("vm2"); !errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("expected ErrPoolExhausted, got %v", err)
+	}
+}
+
+func TestPoolAllocatePath(t *testing.T) {
+	p := newTestPool()
+	const lp = "PCIROOT(0)#PCI(0301)"
+
+	if err := p.AllocatePath("vm1", lp); err != nil {
+		t.Fatal(err)
+	}
+	// Re-reserving for the same VM is a no-op.
+	if err := p.AllocatePath("vm1", lp); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AllocatePath("vm2", lp); !errors.Is(err, ErrDeviceInUse) {
+		t.Fatalf("expected ErrDeviceInUse, got %v", err)
+	}
+	if err := p.AllocatePath("vm1", "PCIROOT(0)#PCI(9999)"); !errors.Is(err, ErrDeviceNotInPool) {
+		t.Fatalf("expected ErrDeviceNotInPool, got %v", err)
+	}
+}
+
+func TestPoolRelease(t *testing.T) {
+	p := newTestPool()
+	lp, err := p.Allocate("vm1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Release(lp); err != nil {
+		t.Fatal(err)
+	}
+	// The device must be allocatable again once released.
+	if err := p.AllocatePath("vm2", lp); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Release("PCIROOT(0)#PCI(9999)"); !errors.Is(err, ErrDeviceNotInPool) {
+		t.Fatalf("expected ErrDeviceNotInPool, got %v", err)
+	}
+}
+
+func TestPoolAssignments(t *testing.T) {
+	p := newTestPool()
+	if err := p.AllocatePath("vm1", "PCIROOT(0)#PCI(0301)"); err != nil {
+		t.Fatal(err)
+	}
+
+	assignments := p.Assignments()
+	expected := []PoolDevice{
+		{LocationPath: "PCIROOT(0)#PCI(0300)", AssignedUVM: ""},
+		{LocationPath: "PCIROOT(0)#PCI(0301)", AssignedUVM: "vm1"},
+		{LocationPath: "PCIROOT(0)#PCI(0302)", AssignedUVM: ""},
+	}
+	if len(assignments) != len(expected) {
+		t.Fatalf("expected %d devices, got %d", len(expected), len(assignments))
+	}
+	for i, d := range assignments {
+		if d != expected[i] {
+			t.Fatalf("assignment %d: expected %+v, got %+v", i, expected[i], d)
+		}
+	}
+}
@@ -51,6 +51,10 @@ type rpc struct {
 	resp    responseMessage
 	brdgErr error // error encountered when sending the request or unmarshaling the result
 	ch      chan struct{}
+	// sensitiveEnv requests that the process environment be scrubbed from
+	// logged copies of the message even when scrubbing is not globally
+	// enabled. It is never part of the wire message.
+	sensitiveEnv bool
 }
 
 // bridge represents a communcations bridge with the guest. It handles the
@@ -143,10 +147,11 @@ func (brdg *bridge) Wait() error {
 // returned.
 func (brdg *bridge) AsyncRPC(ctx context.Context, proc rpcProc, req requestMessage, resp responseMessage) (*rpc, error) {
 	call := &rpc{
-		ch:   make(chan struct{}),
-		proc: proc,
-		req:  req,
-		resp: resp,
+		ch:           make(chan struct{}),
+		proc:         proc,
+		req:          req,
+		resp:         resp,
+		sensitiveEnv: log.IsEnvironmentSensitive(ctx),
 	}
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -381,7 +386,7 @@ func (brdg *bridge) sendLoop() {
 	}
 }
 
-func (brdg *bridge) writeMessage(buf *bytes.Buffer, enc *json.Encoder, typ msgType, id int64, req interface{}) error {
+func (brdg *bridge) writeMessage(buf *bytes.Buffer, enc *json.Encoder, typ msgType, id int64, req interface{}, sensitiveEnv bool) error {
 	var err error
 	_, span := oc.StartSpan(context.Background(), "bridge send", oc.WithClientSpanKind)
 	defer span.End()
@@ -409,7 +414,11 @@ func (brdg *bridge) writeMessage(buf *bytes.Buffer, enc *json.Encoder, typ msgTy
 		case msgType(rpcCreate) | msgTypeRequest:
 			b, err = log.ScrubBridgeCreate(b)
 		case msgType(rpcExecuteProcess) | msgTypeRequest:
-			b, err = log.ScrubBridgeExecProcess(b)
+			scrubCtx := context.Background()
+			if sensitiveEnv {
+				scrubCtx = log.WithSensitiveEnvironment(scrubCtx)
+			}
+			b, err = log.ScrubBridgeExecProcess(scrubCtx, b)
 		}
 		if err != nil {
 			brdg.log.WithError(err).Warning("could not scrub bridge payload")
@@ -442,7 +451,7 @@ func (brdg *bridge) sendRPC(buf *bytes.Buffer, enc *json.Encoder, call *rpc) err
 	brdg.nextID++
 	brdg.mu.Unlock()
 	typ := msgType(call.proc) | msgTypeRequest
-	err := brdg.writeMessage(buf, enc, typ, id, call.req)
+	err := brdg.writeMessage(buf, enc, typ, id, call.req, call.sensitiveEnv)
 	if err != nil {
 		// Try to reclaim this request and fail it.
 		brdg.mu.Lock()
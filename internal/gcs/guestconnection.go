@@ -191,6 +191,58 @@ func (gc *GuestConnection) DumpStacks(ctx context.Context) (response string, err
 	return resp.GuestStacks, err
 }
 
+// ReadFile reads the contents of a file in the guest. It is intended for
+// small configuration files only; larger transfers should use a mounted
+// share.
+func (gc *GuestConnection) ReadFile(ctx context.Context, path string) (contents []byte, err error) {
+	ctx, span := oc.StartSpan(ctx, "gcs::GuestConnection::ReadFile", oc.WithClientSpanKind)
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(trace.StringAttribute("path", path))
+
+	req := readFileRequest{
+		requestBase: makeRequest(ctx, nullContainerID),
+		Path:        path,
+	}
+	var resp readFileResponse
+	err = gc.brdg.RPC(ctx, rpcReadFile, &req, &resp, false)
+	return resp.FileContents, err
+}
+
+// ListMounts enumerates the active mounts inside the guest.
+func (gc *GuestConnection) ListMounts(ctx context.Context) (mounts []GuestMount, err error) {
+	ctx, span := oc.StartSpan(ctx, "gcs::GuestConnection::ListMounts", oc.WithClientSpanKind)
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	req := listMountsRequest{
+		requestBase: makeRequest(ctx, nullContainerID),
+	}
+	var resp listMountsResponse
+	err = gc.brdg.RPC(ctx, rpcListMounts, &req, &resp, false)
+	if err == nil && resp.Truncated {
+		log.G(ctx).Warn("guest mount enumeration was truncated to fit the bridge message size limit")
+	}
+	return resp.Mounts, err
+}
+
+// AttestationReport requests a hardware attestation report from the guest,
+// embedding `reportData` (for example a nonce or key hash) in the signed
+// report so a relying party can verify freshness.
+func (gc *GuestConnection) AttestationReport(ctx context.Context, reportData []byte) (report []byte, err error) {
+	ctx, span := oc.StartSpan(ctx, "gcs::GuestConnection::AttestationReport", oc.WithClientSpanKind)
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	req := attestationReportRequest{
+		requestBase: makeRequest(ctx, nullContainerID),
+		ReportData:  reportData,
+	}
+	var resp attestationReportResponse
+	err = gc.brdg.RPC(ctx, rpcGetAttestationReport, &req, &resp, false)
+	return resp.Report, err
+}
+
 func (gc *GuestConnection) DeleteContainerState(ctx context.Context, cid string) (err error) {
 	ctx, span := oc.StartSpan(ctx, "gcs::GuestConnection::DeleteContainerState", oc.WithClientSpanKind)
 	defer span.End()
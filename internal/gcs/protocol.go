@@ -63,6 +63,9 @@ const (
 	rpcDeleteContainerState
 	rpcUpdateContainer
 	rpcLifecycleNotification
+	rpcReadFile
+	rpcListMounts
+	rpcGetAttestationReport
 )
 
 func (rpc rpcProc) String() string {
@@ -97,6 +100,12 @@ func (rpc rpcProc) String() string {
 		return "UpdateContainer"
 	case rpcLifecycleNotification:
 		return "LifecycleNotification"
+	case rpcReadFile:
+		return "ReadFile"
+	case rpcListMounts:
+		return "ListMounts"
+	case rpcGetAttestationReport:
+		return "GetAttestationReport"
 	default:
 		return "0x" + strconv.FormatUint(uint64(rpc), 16)
 	}
@@ -217,6 +226,47 @@ type dumpStacksResponse struct {
 	GuestStacks string
 }
 
+type readFileRequest struct {
+	requestBase
+	Path string
+}
+
+type readFileResponse struct {
+	responseBase
+	FileContents []byte `json:",omitempty"`
+}
+
+// GuestMount describes a single active mount inside the guest.
+type GuestMount struct {
+	MountPath string `json:",omitempty"`
+	Source    string `json:",omitempty"`
+	ReadOnly  bool   `json:",omitempty"`
+}
+
+type listMountsRequest struct {
+	requestBase
+}
+
+type listMountsResponse struct {
+	responseBase
+	Mounts []GuestMount `json:",omitempty"`
+	// Truncated is set by the guest when the enumeration was cut short to
+	// keep the reply within the bridge's message size limit.
+	Truncated bool `json:",omitempty"`
+}
+
+type attestationReportRequest struct {
+	requestBase
+	// ReportData is the user-supplied data (for example a nonce or key hash)
+	// embedded in the signed report.
+	ReportData []byte `json:",omitempty"`
+}
+
+type attestationReportResponse struct {
+	responseBase
+	Report []byte `json:",omitempty"`
+}
+
 type deleteContainerStateRequest struct {
 	requestBase
 }
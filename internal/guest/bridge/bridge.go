@@ -218,6 +218,9 @@ func (b *Bridge) AssignHandlers(mux *Mux, host *hcsv2.Host) {
 		mux.HandleFunc(prot.ComputeSystemModifySettingsV1, prot.PvV4, b.modifySettingsV2)
 		mux.HandleFunc(prot.ComputeSystemDumpStacksV1, prot.PvV4, b.dumpStacksV2)
 		mux.HandleFunc(prot.ComputeSystemDeleteContainerStateV1, prot.PvV4, b.deleteContainerStateV2)
+		mux.HandleFunc(prot.ComputeSystemReadFileV1, prot.PvV4, b.readFileV2)
+		mux.HandleFunc(prot.ComputeSystemListMountsV1, prot.PvV4, b.listMountsV2)
+		mux.HandleFunc(prot.ComputeSystemGetAttestationReportV1, prot.PvV4, b.getAttestationReportV2)
 	}
 }
 
@@ -317,7 +320,7 @@ func (b *Bridge) ListenAndServe(bridgeIn io.ReadCloser, bridgeOut io.WriteCloser
 					case prot.ComputeSystemCreateV1:
 						msgBytes, err = log.ScrubBridgeCreate(message)
 					case prot.ComputeSystemExecuteProcessV1:
-						msgBytes, err = log.ScrubBridgeExecProcess(message)
+						msgBytes, err = log.ScrubBridgeExecProcess(ctx, message)
 					default:
 						msgBytes = message
 					}
@@ -6,6 +6,7 @@ package bridge
 import (
 	"context"
 	"encoding/json"
+	"os"
 	"syscall"
 	"time"
 
@@ -18,9 +19,12 @@ import (
 	"github.com/Microsoft/hcsshim/internal/guest/prot"
 	"github.com/Microsoft/hcsshim/internal/guest/runtime/hcsv2"
 	"github.com/Microsoft/hcsshim/internal/guest/stdio"
+	"github.com/Microsoft/hcsshim/internal/guest/storage"
 	"github.com/Microsoft/hcsshim/internal/log"
 	"github.com/Microsoft/hcsshim/internal/oc"
 	"github.com/Microsoft/hcsshim/internal/protocol/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestresource"
+	"github.com/Microsoft/hcsshim/pkg/amdsevsnp"
 )
 
 // The capabilities of this GCS.
@@ -441,6 +445,100 @@ func (b *Bridge) modifySettingsV2(r *Request) (_ RequestResponse, err error) {
 	return &prot.MessageResponseBase{}, nil
 }
 
+// readFileV2 returns the contents of a file in the guest. It is intended for
+// small files only; larger transfers should use a mounted share.
+func (b *Bridge) readFileV2(r *Request) (_ RequestResponse, err error) {
+	_, span := oc.StartSpan(r.Context, "opengcs::bridge::readFileV2")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	var request prot.ContainerReadFile
+	if err := commonutils.UnmarshalJSONWithHresult(r.Message, &request); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal JSON in message \"%s\"", r.Message)
+	}
+	span.AddAttributes(trace.StringAttribute("path", request.Path))
+
+	// Reject oversized files before reading them into memory. The check is
+	// repeated on the contents read, since the file may grow in between; a
+	// reply over the bridge's message size limit would tear down the whole
+	// connection.
+	fi, err := os.Stat(request.Path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() > guestresource.MaxGuestFileTransferSize {
+		return nil, errors.Errorf("%d byte file %s exceeds the %d byte guest file limit, use a mounted share instead", fi.Size(), request.Path, guestresource.MaxGuestFileTransferSize)
+	}
+	contents, err := os.ReadFile(request.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(contents) > guestresource.MaxGuestFileTransferSize {
+		return nil, errors.Errorf("%d byte file %s exceeds the %d byte guest file limit, use a mounted share instead", len(contents), request.Path, guestresource.MaxGuestFileTransferSize)
+	}
+	return &prot.ContainerReadFileResponse{
+		FileContents: contents,
+	}, nil
+}
+
+// listMountsV2 enumerates the active mounts in the guest from /proc/mounts.
+func (b *Bridge) listMountsV2(r *Request) (_ RequestResponse, err error) {
+	_, span := oc.StartSpan(r.Context, "opengcs::bridge::listMountsV2")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	mountPoints, err := storage.ListMountPoints()
+	if err != nil {
+		return nil, err
+	}
+	// The host rejects bridge messages over 64KiB and tears down the
+	// connection, so bound the encoded size of the reply and flag
+	// truncation instead of risking an oversized message. The per-entry
+	// estimate covers the JSON field names and separators.
+	const responseByteBudget = 48 * 1024
+	const entryOverhead = 64
+	response := &prot.ContainerListMountsResponse{
+		Mounts: make([]prot.GuestMount, 0, len(mountPoints)),
+	}
+	size := 0
+	for _, mp := range mountPoints {
+		entrySize := len(mp.MountPath) + len(mp.Source) + entryOverhead
+		if size+entrySize > responseByteBudget {
+			response.Truncated = true
+			break
+		}
+		size += entrySize
+		response.Mounts = append(response.Mounts, prot.GuestMount{
+			MountPath: mp.MountPath,
+			Source:    mp.Source,
+			ReadOnly:  mp.ReadOnly,
+		})
+	}
+	return response, nil
+}
+
+// getAttestationReportV2 returns a hardware attestation report with the
+// caller's report data embedded. Only supported on SNP isolated guests; the
+// firmware rejects the request elsewhere.
+func (b *Bridge) getAttestationReportV2(r *Request) (_ RequestResponse, err error) {
+	_, span := oc.StartSpan(r.Context, "opengcs::bridge::getAttestationReportV2")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	var request prot.ContainerGetAttestationReport
+	if err := commonutils.UnmarshalJSONWithHresult(r.Message, &request); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal JSON in message \"%s\"", r.Message)
+	}
+
+	report, err := amdsevsnp.FetchRawSNPReport(request.ReportData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch attestation report")
+	}
+	return &prot.ContainerGetAttestationReportResponse{
+		Report: report,
+	}, nil
+}
+
 func (b *Bridge) dumpStacksV2(r *Request) (_ RequestResponse, err error) {
 	ctx, span := oc.StartSpan(r.Context, "opengcs::bridge::dumpStacksV2")
 	defer span.End()
@@ -14,6 +14,7 @@ import (
 
 	"github.com/Microsoft/hcsshim/internal/guest/prot"
 	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestresource"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
@@ -92,7 +93,13 @@ func NetNSConfig(ctx context.Context, ifStr string, nsPid int, adapter *prot.Net
 	}
 
 	// User requested non-default MTU size
-	if adapter.EncapOverhead != 0 {
+	if adapter.MTU != 0 {
+		// An explicit MTU wins over the EncapOverhead adjustment.
+		entry.WithField("mtu", adapter.MTU).Debug("MTU non-zero, will set MTU")
+		if err = netlink.LinkSetMTU(link, int(adapter.MTU)); err != nil {
+			return errors.Wrapf(err, "netlink.LinkSetMTU(%#v, %d) failed", link, adapter.MTU)
+		}
+	} else if adapter.EncapOverhead != 0 {
 		mtu := link.Attrs().MTU - int(adapter.EncapOverhead)
 		entry.WithField("mtu", mtu).Debug("EncapOverhead non-zero, will set MTU")
 		if err = netlink.LinkSetMTU(link, mtu); err != nil {
@@ -167,6 +174,13 @@ func NetNSConfig(ctx context.Context, ifStr string, nsPid int, adapter *prot.Net
 		entry.Debugf("udhcpc succeeded: %s", cos)
 	}
 
+	// Program any static routes requested for the interface.
+	for _, r := range adapter.Routes {
+		if err := addRouteToLink(ctx, link, r); err != nil {
+			return err
+		}
+	}
+
 	// Add some debug logging
 	if entry.Logger.GetLevel() >= logrus.DebugLevel {
 		curNS, _ := netns.Get()
@@ -186,6 +200,36 @@ func NetNSConfig(ctx context.Context, ifStr string, nsPid int, adapter *prot.Net
 	return nil
 }
 
+func addRouteToLink(ctx context.Context, link netlink.Link, r guestresource.LCOWRoute) error {
+	log.G(ctx).WithFields(logrus.Fields{
+		"link":        link.Attrs().Name,
+		"destination": r.DestinationPrefix,
+		"nextHop":     r.NextHop,
+		"metric":      r.Metric,
+	}).Trace("adding static route")
+	_, dst, err := net.ParseCIDR(r.DestinationPrefix)
+	if err != nil {
+		return errors.Wrapf(err, "parsing route destination %s failed", r.DestinationPrefix)
+	}
+	route := netlink.Route{
+		Scope:     netlink.SCOPE_UNIVERSE,
+		LinkIndex: link.Attrs().Index,
+		Dst:       dst,
+		Priority:  int(r.Metric),
+	}
+	if r.NextHop != "" {
+		gw := net.ParseIP(r.NextHop)
+		if gw == nil {
+			return errors.Errorf("parsing route next hop %s failed", r.NextHop)
+		}
+		route.Gw = gw
+	}
+	if err := netlink.RouteAdd(&route); err != nil {
+		return errors.Wrapf(err, "netlink.RouteAdd(%#v) failed", route)
+	}
+	return nil
+}
+
 func assignIPToLink(ctx context.Context,
 	ifStr string,
 	nsPid int,
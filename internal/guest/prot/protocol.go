@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/Microsoft/hcsshim/internal/guest/commonutils"
+	"github.com/Microsoft/hcsshim/internal/guest/gcserr"
 	hcsschema "github.com/Microsoft/hcsshim/internal/hcs/schema2"
 	"github.com/Microsoft/hcsshim/internal/protocol/guestrequest"
 	"github.com/Microsoft/hcsshim/internal/protocol/guestresource"
@@ -107,6 +108,12 @@ const (
 	ComputeSystemDumpStacksV1 = 0x10100c01
 	// ComputeSystemDeleteContainerStateV1 is the delete container request.
 	ComputeSystemDeleteContainerStateV1 = 0x10100d01
+	// ComputeSystemReadFileV1 is the read guest file request.
+	ComputeSystemReadFileV1 = 0x10101001
+	// ComputeSystemListMountsV1 is the list guest mounts request.
+	ComputeSystemListMountsV1 = 0x10101101
+	// ComputeSystemGetAttestationReportV1 is the attestation report request.
+	ComputeSystemGetAttestationReportV1 = 0x10101201
 
 	// ComputeSystemResponseCreateV1 is the create container response.
 	ComputeSystemResponseCreateV1 = 0x20100101
@@ -137,6 +144,13 @@ const (
 	ComputeSystemResponseNegotiateProtocolV1 = 0x20100b01
 	// ComputeSystemResponseDumpStacksV1 is the dump stack response
 	ComputeSystemResponseDumpStacksV1 = 0x20100c01
+	// ComputeSystemResponseReadFileV1 is the read guest file response.
+	ComputeSystemResponseReadFileV1 = 0x20101001
+	// ComputeSystemResponseListMountsV1 is the list guest mounts response.
+	ComputeSystemResponseListMountsV1 = 0x20101101
+	// ComputeSystemResponseGetAttestationReportV1 is the attestation report
+	// response.
+	ComputeSystemResponseGetAttestationReportV1 = 0x20101201
 
 	// ComputeSystemNotificationV1 is the notification identifier.
 	ComputeSystemNotificationV1 = 0x30100101
@@ -173,6 +187,12 @@ func (mi MessageIdentifier) String() string {
 		return "ComputeSystemDumpStacksV1"
 	case ComputeSystemDeleteContainerStateV1:
 		return "ComputeSystemDeleteContainerStateV1"
+	case ComputeSystemReadFileV1:
+		return "ComputeSystemReadFileV1"
+	case ComputeSystemListMountsV1:
+		return "ComputeSystemListMountsV1"
+	case ComputeSystemGetAttestationReportV1:
+		return "ComputeSystemGetAttestationReportV1"
 	case ComputeSystemResponseCreateV1:
 		return "ComputeSystemResponseCreateV1"
 	case ComputeSystemResponseStartV1:
@@ -197,6 +217,12 @@ func (mi MessageIdentifier) String() string {
 		return "ComputeSystemResponseNegotiateProtocolV1"
 	case ComputeSystemResponseDumpStacksV1:
 		return "ComputeSystemResponseDumpStacksV1"
+	case ComputeSystemResponseReadFileV1:
+		return "ComputeSystemResponseReadFileV1"
+	case ComputeSystemResponseListMountsV1:
+		return "ComputeSystemResponseListMountsV1"
+	case ComputeSystemResponseGetAttestationReportV1:
+		return "ComputeSystemResponseGetAttestationReportV1"
 	case ComputeSystemNotificationV1:
 		return "ComputeSystemNotificationV1"
 	default:
@@ -540,6 +566,18 @@ func UnmarshalContainerModifySettings(b []byte) (*ContainerModifySettings, error
 			return &request, errors.Wrap(err, "failed to unmarshal settings as SCSIDevice")
 		}
 		msr.Settings = msd
+	case guestresource.ResourceTypeSCSITrim:
+		msd := &guestresource.SCSIDevice{}
+		if err := commonutils.UnmarshalJSONWithHresult(msrRawSettings, msd); err != nil {
+			return &request, errors.Wrap(err, "failed to unmarshal settings as SCSIDevice")
+		}
+		msr.Settings = msd
+	case guestresource.ResourceTypeSCSIFilesystemResize:
+		msd := &guestresource.SCSIDevice{}
+		if err := commonutils.UnmarshalJSONWithHresult(msrRawSettings, msd); err != nil {
+			return &request, errors.Wrap(err, "failed to unmarshal settings as SCSIDevice")
+		}
+		msr.Settings = msd
 	case guestresource.ResourceTypeMappedVirtualDisk:
 		mvd := &guestresource.LCOWMappedVirtualDisk{}
 		if err := commonutils.UnmarshalJSONWithHresult(msrRawSettings, mvd); err != nil {
@@ -594,8 +632,29 @@ func UnmarshalContainerModifySettings(b []byte) (*ContainerModifySettings, error
 			return &request, errors.Wrap(err, "failed to unmarshal settings as LCOWSecurityPolicyFragment")
 		}
 		msr.Settings = fragment
+	case guestresource.ResourceTypeGuestFile:
+		gf := &guestresource.GuestFile{}
+		if err := commonutils.UnmarshalJSONWithHresult(msrRawSettings, gf); err != nil {
+			return &request, errors.Wrap(err, "failed to unmarshal settings as GuestFile")
+		}
+		msr.Settings = gf
+	case guestresource.ResourceTypeSecret:
+		sd := &guestresource.SecretDelivery{}
+		if err := commonutils.UnmarshalJSONWithHresult(msrRawSettings, sd); err != nil {
+			return &request, errors.Wrap(err, "failed to unmarshal settings as SecretDelivery")
+		}
+		msr.Settings = sd
+	case guestresource.ResourceTypeContainerFreeze:
+		cf := &guestresource.ContainerFreeze{}
+		if err := commonutils.UnmarshalJSONWithHresult(msrRawSettings, cf); err != nil {
+			return &request, errors.Wrap(err, "failed to unmarshal settings as ContainerFreeze")
+		}
+		msr.Settings = cf
+	case guestresource.ResourceTypeTimeSync:
+		// The request carries no settings.
+		msr.Settings = nil
 	default:
-		return &request, errors.Errorf("invalid ResourceType '%s'", msr.ResourceType)
+		return &request, gcserr.WrapHresult(errors.Errorf("invalid ResourceType '%s'", msr.ResourceType), gcserr.HrNotImpl)
 	}
 	request.Request = &msr
 	return &request, nil
@@ -642,6 +701,60 @@ type DumpStacksResponse struct {
 	GuestStacks string
 }
 
+// ContainerReadFile is the message from the HCS requesting the contents of a
+// file in the guest.
+type ContainerReadFile struct {
+	MessageBase
+	Path string
+}
+
+// ContainerReadFileResponse is the message to the HCS responding to a
+// ContainerReadFile message. It contains the contents of the requested file.
+type ContainerReadFileResponse struct {
+	MessageResponseBase
+	FileContents []byte `json:",omitempty"`
+}
+
+// GuestMount describes a single active mount inside the guest in a
+// ContainerListMountsResponse.
+type GuestMount struct {
+	MountPath string `json:",omitempty"`
+	Source    string `json:",omitempty"`
+	ReadOnly  bool   `json:",omitempty"`
+}
+
+// ContainerListMounts is the message from the HCS requesting an enumeration
+// of the active mounts in the guest.
+type ContainerListMounts struct {
+	MessageBase
+}
+
+// ContainerListMountsResponse is the message to the HCS responding to a
+// ContainerListMounts message.
+type ContainerListMountsResponse struct {
+	MessageResponseBase
+	Mounts []GuestMount `json:",omitempty"`
+	// Truncated indicates the enumeration was cut short to keep the
+	// response within the bridge's message size limit.
+	Truncated bool `json:",omitempty"`
+}
+
+// ContainerGetAttestationReport is the message from the HCS requesting a
+// hardware attestation report from the guest.
+type ContainerGetAttestationReport struct {
+	MessageBase
+	// ReportData is the user-supplied data (for example a nonce or key hash)
+	// embedded in the signed report.
+	ReportData []byte `json:",omitempty"`
+}
+
+// ContainerGetAttestationReportResponse is the message to the HCS responding
+// to a ContainerGetAttestationReport message.
+type ContainerGetAttestationReportResponse struct {
+	MessageResponseBase
+	Report []byte `json:",omitempty"`
+}
+
 // ContainerCreateResponse is the message to the HCS responding to a
 // ContainerCreate message. It serves a protocol negotiation function as well
 // for protocol versions 3 and lower, returning protocol version information to
@@ -693,6 +806,12 @@ type NetworkAdapter struct {
 	HostDNSSuffix        string `json:"HostDnsSuffix,omitempty"`
 	EnableLowMetric      bool   `json:",omitempty"`
 	EncapOverhead        uint16 `json:",omitempty"`
+	// MTU, if non-zero, is set on the interface explicitly, taking
+	// precedence over the EncapOverhead adjustment.
+	MTU uint16 `json:",omitempty"`
+	// Routes are static routes to program on the interface in addition to
+	// the gateway default routes.
+	Routes []guestresource.LCOWRoute `json:",omitempty"`
 }
 
 // MappedVirtualDisk represents a disk on the host which is mapped into a
@@ -272,6 +272,20 @@ func (c *Container) modifyContainerConstraints(ctx context.Context, rt guestrequ
 	return c.Update(ctx, cc.Linux)
 }
 
+// modifyContainerFreeze freezes or thaws all of the container's processes via
+// the runtime's pause and resume operations (the cgroup freezer).
+func (c *Container) modifyContainerFreeze(_ context.Context, rt guestrequest.RequestType, cf *guestresource.ContainerFreeze) error {
+	switch rt {
+	case guestrequest.RequestTypeUpdate:
+		if cf.Frozen {
+			return c.container.Pause()
+		}
+		return c.container.Resume()
+	default:
+		return newInvalidRequestTypeError(rt)
+	}
+}
+
 func (c *Container) getStatus() containerStatus {
 	val := atomic.LoadUint32((*uint32)(&c.status))
 	return containerStatus(val)
@@ -262,6 +262,8 @@ func (nin *nicInNamespace) assignToPid(ctx context.Context, pid int) (err error)
 		HostIPv6PrefixLength: nin.adapter.IPv6PrefixLength,
 		EnableLowMetric:      nin.adapter.EnableLowMetric,
 		EncapOverhead:        nin.adapter.EncapOverhead,
+		MTU:                  nin.adapter.MTU,
+		Routes:               nin.adapter.Routes,
 	}
 
 	if err := network.MoveInterfaceToNS(nin.ifname, pid); err != nil {
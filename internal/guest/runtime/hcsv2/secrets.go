@@ -0,0 +1,70 @@
+//go:build linux
+// +build linux
+
+package hcsv2
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+
+	"github.com/Microsoft/hcsshim/internal/guest/storage"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestresource"
+)
+
+// modifySecretDelivery materializes a host-delivered secret as a file under a
+// tmpfs mount, so the payload only ever lives in guest memory and never
+// reaches the scratch disk. Delivering a secret with the name of an existing
+// one replaces it.
+func modifySecretDelivery(_ context.Context, rt guestrequest.RequestType, sd *guestresource.SecretDelivery) error {
+	switch rt {
+	case guestrequest.RequestTypeAdd:
+		if sd.Name == "" || sd.Name == "." || sd.Name == ".." || strings.ContainsAny(sd.Name, `/\`) {
+			return errors.Errorf("invalid secret name %q", sd.Name)
+		}
+		if sd.MountPath == "" || !filepath.IsAbs(sd.MountPath) {
+			return errors.Errorf("secret mount path %q must be absolute", sd.MountPath)
+		}
+		if err := os.MkdirAll(sd.MountPath, 0700); err != nil {
+			return errors.Wrapf(err, "failed to create secret mount point %s", sd.MountPath)
+		}
+		mounted, err := isMountPoint(sd.MountPath)
+		if err != nil {
+			return err
+		}
+		if !mounted {
+			flags := uintptr(unix.MS_NODEV | unix.MS_NOSUID | unix.MS_NOEXEC)
+			if err := unix.Mount("tmpfs", sd.MountPath, "tmpfs", flags, "mode=0700"); err != nil {
+				return errors.Wrapf(err, "failed to mount tmpfs at %s", sd.MountPath)
+			}
+		}
+		path := filepath.Join(sd.MountPath, sd.Name)
+		if err := os.WriteFile(path, sd.Payload, 0600); err != nil {
+			return errors.Wrapf(err, "failed to write secret %s", path)
+		}
+		// WriteFile only applies the mode when creating the file; enforce it
+		// when replacing an existing secret as well.
+		return os.Chmod(path, 0600)
+	default:
+		return newInvalidRequestTypeError(rt)
+	}
+}
+
+// isMountPoint returns whether path is already the target of a mount.
+func isMountPoint(path string) (bool, error) {
+	mountPoints, err := storage.ListMountPoints()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list mount points")
+	}
+	for _, mp := range mountPoints {
+		if mp.MountPath == path {
+			return true, nil
+		}
+	}
+	return false, nil
+}
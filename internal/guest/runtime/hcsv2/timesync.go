@@ -0,0 +1,53 @@
+//go:build linux
+// +build linux
+
+package hcsv2
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+)
+
+const ptpClassPath = "/sys/class/ptp"
+
+// syncGuestTime sets the guest's wall clock from the Hyper-V PTP device,
+// which exposes the host's clock to the guest. This corrects the drift
+// accumulated while the VM was paused or saved, when the guest clock stands
+// still but the host's does not.
+func syncGuestTime(ctx context.Context) error {
+	entries, err := os.ReadDir(ptpClassPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to enumerate PTP devices")
+	}
+	for _, entry := range entries {
+		name, err := os.ReadFile(filepath.Join(ptpClassPath, entry.Name(), "clock_name"))
+		if err != nil || strings.TrimSpace(string(name)) != "hyperv" {
+			continue
+		}
+		f, err := os.Open(filepath.Join("/dev", entry.Name()))
+		if err != nil {
+			return errors.Wrapf(err, "failed to open PTP device %s", entry.Name())
+		}
+		defer f.Close()
+		// FD_TO_CLOCKID, as defined by the kernel's dynamic posix clock API.
+		clockID := int32(^f.Fd()<<3 | 3)
+		var ts unix.Timespec
+		if err := unix.ClockGettime(clockID, &ts); err != nil {
+			return errors.Wrap(err, "failed to read host time from PTP device")
+		}
+		tv := unix.NsecToTimeval(ts.Nano())
+		if err := unix.Settimeofday(&tv); err != nil {
+			return errors.Wrap(err, "failed to set guest time")
+		}
+		log.G(ctx).Info("synchronized guest clock with host")
+		return nil
+	}
+	return errors.New("no Hyper-V PTP clock device found")
+}
@@ -547,6 +547,10 @@ func (h *Host) modifyHostSettings(ctx context.Context, containerID string, req *
 	switch req.ResourceType {
 	case guestresource.ResourceTypeSCSIDevice:
 		return modifySCSIDevice(ctx, req.RequestType, req.Settings.(*guestresource.SCSIDevice))
+	case guestresource.ResourceTypeSCSITrim:
+		return modifySCSITrim(ctx, req.RequestType, req.Settings.(*guestresource.SCSIDevice))
+	case guestresource.ResourceTypeSCSIFilesystemResize:
+		return modifySCSIFilesystemResize(ctx, req.RequestType, req.Settings.(*guestresource.SCSIDevice))
 	case guestresource.ResourceTypeMappedVirtualDisk:
 		mvd := req.Settings.(*guestresource.LCOWMappedVirtualDisk)
 		// find the actual controller number on the bus and update the incoming request.
@@ -618,8 +622,17 @@ func (h *Host) modifyHostSettings(ctx context.Context, containerID string, req *
 			return errors.New("the request settings are not of type LCOWSecurityPolicyFragment")
 		}
 		return h.InjectFragment(ctx, r)
+	case guestresource.ResourceTypeGuestFile:
+		return modifyGuestFile(ctx, req.RequestType, req.Settings.(*guestresource.GuestFile))
+	case guestresource.ResourceTypeSecret:
+		return modifySecretDelivery(ctx, req.RequestType, req.Settings.(*guestresource.SecretDelivery))
+	case guestresource.ResourceTypeTimeSync:
+		if req.RequestType != guestrequest.RequestTypeUpdate {
+			return newInvalidRequestTypeError(req.RequestType)
+		}
+		return syncGuestTime(ctx)
 	default:
-		return errors.Errorf("the ResourceType %q is not supported for UVM", req.ResourceType)
+		return gcserr.WrapHresult(errors.Errorf("the ResourceType %q is not supported for UVM", req.ResourceType), gcserr.HrNotImpl)
 	}
 }
 
@@ -632,8 +645,10 @@ func (h *Host) modifyContainerSettings(ctx context.Context, containerID string,
 	switch req.ResourceType {
 	case guestresource.ResourceTypeContainerConstraints:
 		return c.modifyContainerConstraints(ctx, req.RequestType, req.Settings.(*guestresource.LCOWContainerConstraints))
+	case guestresource.ResourceTypeContainerFreeze:
+		return c.modifyContainerFreeze(ctx, req.RequestType, req.Settings.(*guestresource.ContainerFreeze))
 	default:
-		return errors.Errorf("the ResourceType \"%s\" is not supported for containers", req.ResourceType)
+		return gcserr.WrapHresult(errors.Errorf("the ResourceType \"%s\" is not supported for containers", req.ResourceType), gcserr.HrNotImpl)
 	}
 }
 
@@ -962,6 +977,73 @@ func modifySCSIDevice(
 	}
 }
 
+func modifySCSITrim(
+	ctx context.Context,
+	rt guestrequest.RequestType,
+	msd *guestresource.SCSIDevice,
+) error {
+	switch rt {
+	case guestrequest.RequestTypeUpdate:
+		cNum, err := scsi.ActualControllerNumber(ctx, msd.Controller)
+		if err != nil {
+			return err
+		}
+		return scsi.TrimDevice(ctx, cNum, msd.Lun)
+	default:
+		return newInvalidRequestTypeError(rt)
+	}
+}
+
+func modifySCSIFilesystemResize(
+	ctx context.Context,
+	rt guestrequest.RequestType,
+	msd *guestresource.SCSIDevice,
+) error {
+	switch rt {
+	case guestrequest.RequestTypeUpdate:
+		cNum, err := scsi.ActualControllerNumber(ctx, msd.Controller)
+		if err != nil {
+			return err
+		}
+		return scsi.ExpandDevice(ctx, cNum, msd.Lun)
+	default:
+		return newInvalidRequestTypeError(rt)
+	}
+}
+
+func modifyGuestFile(
+	_ context.Context,
+	rt guestrequest.RequestType,
+	gf *guestresource.GuestFile,
+) error {
+	switch rt {
+	case guestrequest.RequestTypeAdd:
+		if gf.Path == "" || !filepath.IsAbs(gf.Path) {
+			return errors.Errorf("guest file path %q must be absolute", gf.Path)
+		}
+		// Enforce the transfer bound here as well rather than trusting the
+		// host to have checked it.
+		if len(gf.Contents) > guestresource.MaxGuestFileTransferSize {
+			return errors.Errorf("%d byte transfer to %s exceeds the %d byte guest file limit, use a mounted share instead", len(gf.Contents), gf.Path, guestresource.MaxGuestFileTransferSize)
+		}
+		mode := os.FileMode(gf.Mode)
+		if mode == 0 {
+			mode = 0600
+		}
+		if err := os.MkdirAll(filepath.Dir(gf.Path), 0755); err != nil {
+			return errors.Wrapf(err, "failed to create parent directory for guest file %s", gf.Path)
+		}
+		if err := os.WriteFile(gf.Path, gf.Contents, mode); err != nil {
+			return errors.Wrapf(err, "failed to write guest file %s", gf.Path)
+		}
+		// WriteFile only applies the mode when creating the file; enforce it
+		// when replacing an existing one as well.
+		return os.Chmod(gf.Path, mode)
+	default:
+		return newInvalidRequestTypeError(rt)
+	}
+}
+
 func modifyMappedVirtualDisk(
 	ctx context.Context,
 	rt guestrequest.RequestType,
@@ -25,3 +25,22 @@ func Format(ctx context.Context, source string) error {
 	}
 	return nil
 }
+
+// resize2fsCommand runs resize2fs with the provided arguments
+func resize2fsCommand(args []string) error {
+	cmd := exec.Command("resize2fs", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to execute resize2fs: %s: %w", string(output), err)
+	}
+	return nil
+}
+
+// Expand grows the ext4 filesystem on source to fill the underlying device.
+// resize2fs supports online resizing, so source may be mounted.
+func Expand(ctx context.Context, source string) error {
+	if err := resize2fsCommand([]string{source}); err != nil {
+		return fmt.Errorf("resize2fs failed to expand %s: %w", source, err)
+	}
+	return nil
+}
@@ -159,6 +159,42 @@ func UnmountAllInPath(ctx context.Context, path string, removeTarget bool) (err
 	return nil
 }
 
+// MountPoint describes a single active mount parsed from /proc/mounts.
+type MountPoint struct {
+	Source    string
+	MountPath string
+	ReadOnly  bool
+}
+
+// ListMountPoints enumerates the active mounts in the guest from /proc/mounts.
+func ListMountPoints() ([]MountPoint, error) {
+	var mountPoints []MountPoint
+	f, err := os.Open(procMountFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), " ")
+		if len(fields) < numProcMountFields {
+			continue
+		}
+		mp := MountPoint{
+			Source:    fields[0],
+			MountPath: fields[1],
+		}
+		for _, o := range strings.Split(fields[3], ",") {
+			if o == "ro" {
+				mp.ReadOnly = true
+				break
+			}
+		}
+		mountPoints = append(mountPoints, mp)
+	}
+	return mountPoints, scanner.Err()
+}
+
 func listMountPointsUnderPath(path string) ([]string, error) {
 	var mountPoints []string
 	f, err := os.Open(procMountFile)
@@ -13,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unsafe"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -20,6 +21,7 @@ import (
 	"golang.org/x/sys/unix"
 
 	"github.com/Microsoft/hcsshim/ext4/tar2ext4"
+	"github.com/Microsoft/hcsshim/internal/guest/linux"
 	"github.com/Microsoft/hcsshim/internal/guest/storage"
 	"github.com/Microsoft/hcsshim/internal/guest/storage/crypt"
 	dm "github.com/Microsoft/hcsshim/internal/guest/storage/devicemapper"
@@ -61,6 +63,8 @@ var (
 	// ext4Format is stubbed for unit testing the `EnsureFilesystem` flow
 	// in `mount`
 	ext4Format = ext4.Format
+	// ext4Expand is stubbed for unit testing `ExpandDevice`
+	ext4Expand = ext4.Expand
 	// ext4Format is stubbed for unit testing the `EnsureFilesystem` and
 	// `Encrypt` flow in `mount`
 	xfsFormat = xfs.Format
@@ -452,6 +456,85 @@ func UnplugDevice(ctx context.Context, controller, lun uint8) (err error) {
 	return nil
 }
 
+// ExpandDevice rescans the SCSI device on `controller` index `lun` so the
+// guest kernel picks up its new capacity, then grows the filesystem on it to
+// fill the device. The device may be mounted; the resize is performed online.
+//
+// Only ext4 filesystems are supported.
+func ExpandDevice(ctx context.Context, controller, lun uint8) (err error) {
+	ctx, span := oc.StartSpan(ctx, "scsi::ExpandDevice")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	span.AddAttributes(
+		trace.Int64Attribute("controller", int64(controller)),
+		trace.Int64Attribute("lun", int64(lun)))
+
+	scsiID := fmt.Sprintf("%d:0:0:%d", controller, lun)
+	f, err := os.OpenFile(filepath.Join(scsiDevicesPath, scsiID, "rescan"), os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write([]byte("1\n")); err != nil {
+		f.Close()
+		return err
+	}
+	f.Close()
+
+	devicePath, err := getDevicePath(ctx, controller, lun, 0)
+	if err != nil {
+		return err
+	}
+
+	fsType, err := _getDeviceFsType(devicePath)
+	if err != nil {
+		return err
+	}
+	if fsType != "ext4" {
+		return errors.Errorf("unsupported filesystem type %q on device %s", fsType, devicePath)
+	}
+	return ext4Expand(ctx, devicePath)
+}
+
+// TrimDevice flushes outstanding writes to the SCSI device on `controller`
+// index `lun` and discards all of its blocks, so that a sparse virtual disk
+// backing it on the host can release the space. The device's contents are
+// destroyed; this is only issued ahead of detaching a disk whose contents are
+// no longer needed, such as a scratch disk.
+func TrimDevice(ctx context.Context, controller, lun uint8) (err error) {
+	ctx, span := oc.StartSpan(ctx, "scsi::TrimDevice")
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+
+	span.AddAttributes(
+		trace.Int64Attribute("controller", int64(controller)),
+		trace.Int64Attribute("lun", int64(lun)))
+
+	devicePath, err := getDevicePath(ctx, controller, lun, 0)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(devicePath, os.O_RDWR, 0)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open device %s", devicePath)
+	}
+	defer f.Close()
+
+	// Flush any buffered writes so the discard does not race them.
+	if err := unix.Fsync(int(f.Fd())); err != nil {
+		return errors.Wrapf(err, "failed to flush device %s", devicePath)
+	}
+	var size uint64
+	if err := linux.Ioctl(f, unix.BLKGETSIZE64, unsafe.Pointer(&size)); err != nil {
+		return errors.Wrapf(err, "failed to get size of device %s", devicePath)
+	}
+	dRange := [2]uint64{0, size}
+	if err := linux.Ioctl(f, unix.BLKDISCARD, unsafe.Pointer(&dRange)); err != nil {
+		return errors.Wrapf(err, "failed to discard device %s", devicePath)
+	}
+	return nil
+}
+
 var ErrUnknownFilesystem = errors.New("could not get device filesystem type")
 
 // getDeviceFsType finds a device's filesystem.
@@ -0,0 +1,48 @@
+//go:build windows
+
+package hcs
+
+import (
+	"context"
+
+	"github.com/Microsoft/hcsshim/internal/hcs/schema1"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+)
+
+// HostProcess is a process list entry tagged with the ID of the compute
+// system that owns the process.
+type HostProcess struct {
+	SystemID string
+	schema1.ProcessListItem
+}
+
+// AllProcesses enumerates every compute system on the host and returns a flat
+// list of all of their processes. Systems that cannot be queried (for
+// instance because they stopped mid-enumeration) are skipped with a logged
+// warning rather than failing the whole enumeration.
+func AllProcesses(ctx context.Context) ([]HostProcess, error) {
+	systems, err := GetComputeSystems(ctx, schema1.ComputeSystemQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []HostProcess
+	for _, props := range systems {
+		system, err := OpenComputeSystem(ctx, props.ID)
+		if err != nil {
+			log.G(ctx).WithError(err).WithField(logfields.ContainerID, props.ID).Warn("failed to open compute system during host-wide process enumeration")
+			continue
+		}
+		sysProps, err := system.Properties(ctx, schema1.PropertyTypeProcessList)
+		system.Close()
+		if err != nil {
+			log.G(ctx).WithError(err).WithField(logfields.ContainerID, props.ID).Warn("failed to query process list during host-wide process enumeration")
+			continue
+		}
+		for _, p := range sysProps.ProcessList {
+			results = append(results, HostProcess{SystemID: props.ID, ProcessListItem: p})
+		}
+	}
+	return results, nil
+}
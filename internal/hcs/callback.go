@@ -11,6 +11,7 @@ import (
 	"github.com/Microsoft/hcsshim/internal/logfields"
 	"github.com/Microsoft/hcsshim/internal/vmcompute"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows"
 )
 
 var (
@@ -95,6 +96,16 @@ type notificationWatcherContext struct {
 
 	systemID  string
 	processID int
+
+	// crashLock guards crashReport, which holds the raw payload of a guest
+	// crash-report notification until it is collected at exit.
+	crashLock   sync.Mutex
+	crashReport string
+
+	// subscriberLock guards subscribers, which receive typed copies of the
+	// lifecycle notifications delivered to this callback context.
+	subscriberLock sync.Mutex
+	subscribers    []*systemEventSubscriber
 }
 
 type notificationChannels map[hcsNotification]notificationChannel
@@ -155,6 +166,16 @@ func notificationWatcher(notificationType hcsNotification, callbackNumber uintpt
 	}
 	log.Debug("HCS notification")
 
+	if notificationType == hcsNotificationSystemCrashReport && notificationData != nil {
+		context.crashLock.Lock()
+		context.crashReport = windows.UTF16PtrToString(notificationData)
+		context.crashLock.Unlock()
+	}
+
+	if context.processID == 0 {
+		context.publish(notificationType, result, notificationData)
+	}
+
 	if channel, ok := context.channels[notificationType]; ok {
 		channel <- result
 	}
@@ -0,0 +1,62 @@
+//go:build windows
+
+package hcs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+)
+
+// CrashReport holds a guest crash report delivered by HCS when the guest
+// kernel panics. The structured fields are best-effort parses of the common
+// report members; RawReport always preserves the full notification payload.
+type CrashReport struct {
+	SystemID        string   `json:"SystemId,omitempty"`
+	ActivityID      string   `json:"ActivityId,omitempty"`
+	CrashParameters []uint64 `json:"CrashParameters,omitempty"`
+	CrashLog        string   `json:"CrashLog,omitempty"`
+	DumpLocation    string   `json:"DumpLocation,omitempty"`
+	RawReport       string   `json:"-"`
+}
+
+// fetchCrashReport collects any crash-report notification payload captured by
+// the notification watcher for this system's callback. Returns nil if no
+// crash report was received.
+func (computeSystem *System) fetchCrashReport(ctx context.Context) *CrashReport {
+	callbackMapLock.RLock()
+	callbackContext := callbackMap[computeSystem.callbackNumber]
+	callbackMapLock.RUnlock()
+	if callbackContext == nil {
+		return nil
+	}
+
+	callbackContext.crashLock.Lock()
+	raw := callbackContext.crashReport
+	callbackContext.crashLock.Unlock()
+	if raw == "" {
+		return nil
+	}
+
+	report := &CrashReport{RawReport: raw}
+	if err := json.Unmarshal([]byte(raw), report); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to parse guest crash report; only the raw payload is available")
+	}
+	return report
+}
+
+// CrashReport returns the guest crash report received for this compute
+// system, if any. A report is only available once the system has exited;
+// calling this on a running system, or on one that exited without a
+// crash-report notification, returns an error.
+func (computeSystem *System) CrashReport() (*CrashReport, error) {
+	if !computeSystem.stopped() {
+		return nil, errors.New("container not exited")
+	}
+	if computeSystem.crashReport == nil {
+		return nil, errors.New("no guest crash report was received")
+	}
+	return computeSystem.crashReport, nil
+}
@@ -27,6 +27,10 @@ var (
 	// decimal -2147024883 / hex 0x8007000d
 	ErrInvalidData = syscall.Errno(0xd)
 
+	// ErrNotImplemented is the E_NOTIMPL HRESULT returned by guests that do not
+	// implement a request
+	ErrNotImplemented = syscall.Errno(0x80004001)
+
 	// ErrHandleClose is an error encountered when the handle generating the notification being waited on has been closed
 	ErrHandleClose = errors.New("hcsshim: the handle generating this notification has been closed")
 
@@ -81,6 +85,11 @@ var (
 	// ErrVmcomputeUnexpectedExit is an error encountered when the compute system terminates unexpectedly
 	ErrVmcomputeUnexpectedExit = syscall.Errno(0xC0370106)
 
+	// ErrExitDetectedByPoll is an error encountered when a compute system's exit was
+	// discovered by polling its properties because the HCS exit notification was never
+	// delivered
+	ErrExitDetectedByPoll = errors.New("hcsshim: system exit detected by poll")
+
 	// ErrNotSupported is an error encountered when hcs doesn't support the request
 	ErrPlatformNotSupported = errors.New("unsupported platform request")
 
@@ -167,6 +176,18 @@ func (e *HcsError) Unwrap() error {
 	return e.Err
 }
 
+// HResult returns the Windows error code underlying the error, or 0 if the
+// wrapped error did not originate from a system error code. Together with the
+// exported Op and Events fields this lets callers use errors.As to inspect a
+// failed operation without parsing the flattened error string.
+func (e *HcsError) HResult() syscall.Errno {
+	var errno syscall.Errno
+	if errors.As(e.Err, &errno) {
+		return errno
+	}
+	return 0
+}
+
 // Deprecated: net.Error.Temporary is deprecated.
 func (e *HcsError) Temporary() bool {
 	err := e.netError()
@@ -310,7 +331,7 @@ func IsAlreadyStopped(err error) bool {
 // is thrown from the Platform
 func IsNotSupported(err error) bool {
 	// If Platform doesn't recognize or support the request sent, below errors are seen
-	return IsAny(err, ErrVmcomputeInvalidJSON, ErrInvalidData, ErrNotSupported, ErrVmcomputeUnknownMessage)
+	return IsAny(err, ErrVmcomputeInvalidJSON, ErrInvalidData, ErrNotSupported, ErrVmcomputeUnknownMessage, ErrNotImplemented)
 }
 
 // IsOperationInvalidState returns true when err is caused by
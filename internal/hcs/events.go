@@ -0,0 +1,160 @@
+//go:build windows
+
+package hcs
+
+import (
+	"context"
+
+	"golang.org/x/sys/windows"
+)
+
+// SystemEventType identifies the lifecycle notification carried by a
+// [SystemEvent].
+type SystemEventType string
+
+const (
+	SystemEventExited                 SystemEventType = "Exited"
+	SystemEventPaused                 SystemEventType = "Paused"
+	SystemEventResumed                SystemEventType = "Resumed"
+	SystemEventSaved                  SystemEventType = "Saved"
+	SystemEventGuestCrashInitiated    SystemEventType = "GuestCrashInitiated"
+	SystemEventGuestCrashed           SystemEventType = "GuestCrashed"
+	SystemEventRdpEnhancedModeChanged SystemEventType = "RdpEnhancedModeChanged"
+	SystemEventShutdownFailed         SystemEventType = "ShutdownFailed"
+	SystemEventGuestConnectionClosed  SystemEventType = "GuestConnectionClosed"
+	SystemEventServiceDisconnected    SystemEventType = "ServiceDisconnected"
+)
+
+// systemEventTypes maps the HCS notifications that are interesting to
+// subscribers to their exported event types. Operation-completion
+// notifications (create, start, get-properties, modify) are deliberately
+// excluded; they are consumed by the originating calls.
+var systemEventTypes = map[hcsNotification]SystemEventType{
+	hcsNotificationSystemExited:                      SystemEventExited,
+	hcsNotificationSystemPauseCompleted:              SystemEventPaused,
+	hcsNotificationSystemResumeCompleted:             SystemEventResumed,
+	hcsNotificationSystemSaveCompleted:               SystemEventSaved,
+	hcsNotificationSystemCrashInitiated:              SystemEventGuestCrashInitiated,
+	hcsNotificationSystemCrashReport:                 SystemEventGuestCrashed,
+	hcsNotificationSystemRdpEnhancedModeStateChanged: SystemEventRdpEnhancedModeChanged,
+	hcsNotificationSystemShutdownFailed:              SystemEventShutdownFailed,
+	hcsNotificationSystemGuestConnectionClosed:       SystemEventGuestConnectionClosed,
+	hcsNotificationServiceDisconnect:                 SystemEventServiceDisconnected,
+}
+
+// SystemEvent is a typed lifecycle notification from the HCS for a compute
+// system.
+type SystemEvent struct {
+	Type SystemEventType
+	// Result carries the error delivered with the notification, if any.
+	Result error
+	// Data is the raw payload attached to the notification, such as a guest
+	// crash report. Empty for notifications that carry no payload.
+	Data string
+}
+
+// systemEventSubscriber is one Subscribe caller's view of the notification
+// stream for a callback context.
+type systemEventSubscriber struct {
+	ch chan SystemEvent
+	// done is closed when the subscription is torn down, either by the
+	// subscriber's context or by the system's callbacks being unregistered.
+	done chan struct{}
+}
+
+// subscriberEventBuffer is the per-subscriber channel depth. Events beyond it
+// are dropped rather than blocking the HCS notification thread.
+const subscriberEventBuffer = 16
+
+func (context *notificationWatcherContext) addSubscriber() *systemEventSubscriber {
+	s := &systemEventSubscriber{
+		ch:   make(chan SystemEvent, subscriberEventBuffer),
+		done: make(chan struct{}),
+	}
+	context.subscriberLock.Lock()
+	context.subscribers = append(context.subscribers, s)
+	context.subscriberLock.Unlock()
+	return s
+}
+
+func (context *notificationWatcherContext) removeSubscriber(s *systemEventSubscriber) {
+	context.subscriberLock.Lock()
+	defer context.subscriberLock.Unlock()
+	for i, sub := range context.subscribers {
+		if sub == s {
+			context.subscribers = append(context.subscribers[:i], context.subscribers[i+1:]...)
+			close(s.ch)
+			close(s.done)
+			return
+		}
+	}
+}
+
+func (context *notificationWatcherContext) closeSubscribers() {
+	context.subscriberLock.Lock()
+	subscribers := context.subscribers
+	context.subscribers = nil
+	context.subscriberLock.Unlock()
+	for _, s := range subscribers {
+		close(s.ch)
+		close(s.done)
+	}
+}
+
+// publish fans a notification out to the context's subscribers. It must not
+// block: sends to subscribers that are not keeping up are dropped.
+func (context *notificationWatcherContext) publish(notificationType hcsNotification, result error, notificationData *uint16) {
+	typ, ok := systemEventTypes[notificationType]
+	if !ok {
+		return
+	}
+	event := SystemEvent{Type: typ, Result: result}
+	if notificationData != nil {
+		event.Data = windows.UTF16PtrToString(notificationData)
+	}
+	context.subscriberLock.Lock()
+	defer context.subscriberLock.Unlock()
+	for _, s := range context.subscribers {
+		select {
+		case s.ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of typed lifecycle notifications for the compute
+// system, beyond the exit surfaced by Wait: pause/resume and save completions,
+// guest crashes, RDP enhanced mode changes, shutdown failures, and guest
+// connection loss. Events are dropped rather than delivered late if the
+// subscriber does not keep up with the channel's buffer.
+//
+// The channel is closed when `ctx` is cancelled or the system's notification
+// callbacks are unregistered on close.
+func (computeSystem *System) Subscribe(ctx context.Context) (<-chan SystemEvent, error) {
+	computeSystem.handleLock.RLock()
+	defer computeSystem.handleLock.RUnlock()
+
+	operation := "hcs::System::Subscribe"
+
+	if computeSystem.handle == 0 {
+		return nil, makeSystemError(computeSystem, operation, ErrAlreadyClosed, nil)
+	}
+
+	callbackMapLock.RLock()
+	callbackContext := callbackMap[computeSystem.callbackNumber]
+	callbackMapLock.RUnlock()
+
+	if callbackContext == nil {
+		return nil, makeSystemError(computeSystem, operation, ErrHandleClose, nil)
+	}
+
+	s := callbackContext.addSubscriber()
+	go func() {
+		select {
+		case <-ctx.Done():
+			callbackContext.removeSubscriber(s)
+		case <-s.done:
+		}
+	}()
+	return s.ch, nil
+}
@@ -0,0 +1,56 @@
+//go:build windows
+
+package hcs
+
+import (
+	"testing"
+)
+
+func TestSystemEventPublish(t *testing.T) {
+	ctx := &notificationWatcherContext{}
+	s := ctx.addSubscriber()
+
+	ctx.publish(hcsNotificationSystemPauseCompleted, nil, nil)
+	select {
+	case event := <-s.ch:
+		if event.Type != SystemEventPaused {
+			t.Fatalf("expected event type %v, got %v", SystemEventPaused, event.Type)
+		}
+	default:
+		t.Fatal("expected an event to be delivered")
+	}
+
+	// Notifications without a subscriber-facing event type are not delivered.
+	ctx.publish(hcsNotificationSystemCreateCompleted, nil, nil)
+	select {
+	case event := <-s.ch:
+		t.Fatalf("unexpected event delivered: %v", event.Type)
+	default:
+	}
+
+	ctx.removeSubscriber(s)
+	if _, ok := <-s.ch; ok {
+		t.Fatal("expected channel to be closed after removal")
+	}
+	select {
+	case <-s.done:
+	default:
+		t.Fatal("expected done to be closed after removal")
+	}
+
+	// Removing again is a no-op.
+	ctx.removeSubscriber(s)
+}
+
+func TestSystemEventPublishDropsWhenFull(t *testing.T) {
+	ctx := &notificationWatcherContext{}
+	s := ctx.addSubscriber()
+	defer ctx.closeSubscribers()
+
+	for i := 0; i < subscriberEventBuffer+5; i++ {
+		ctx.publish(hcsNotificationSystemResumeCompleted, nil, nil)
+	}
+	if got := len(s.ch); got != subscriberEventBuffer {
+		t.Fatalf("expected %d buffered events, got %d", subscriberEventBuffer, got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -32,23 +33,75 @@ type Process struct {
 	stdin               io.WriteCloser
 	stdout              io.ReadCloser
 	stderr              io.ReadCloser
-	callbackNumber      uintptr
-	killSignalDelivered bool
+	callbackNumber uintptr
+	// killSignalDelivered is nonzero once a kill signal has been delivered
+	// to the process. It is read by ExitStatus, which may race a concurrent
+	// Kill; only access atomically.
+	killSignalDelivered int32
+
+	// stdioDrain tracks outstanding stdout/stderr relays registered with
+	// RegisterStdioRelay. When a drain timeout is configured, the exit
+	// notification and Close wait (bounded) for the relays to flush before
+	// the pipes are broken, so trailing output is not lost.
+	stdioDrain        sync.WaitGroup
+	stdioDrainTimeout time.Duration
 
 	closedWaitOnce sync.Once
 	waitBlock      chan struct{}
 	exitCode       int
 	waitError      error
+	startTime      time.Time
+	exitTime       time.Time
 }
 
 var _ cow.Process = &Process{}
 
 func newProcess(process vmcompute.HcsProcess, processID int, computeSystem *System) *Process {
-	return &Process{
+	p := &Process{
 		handle:    process,
 		processID: processID,
 		system:    computeSystem,
 		waitBlock: make(chan struct{}),
+		startTime: time.Now(),
+	}
+	trackProcess(p)
+	return p
+}
+
+// SetStdioDrainTimeout enables stdio draining for the process: once the exit
+// notification arrives, up to `timeout` is allowed for relays registered via
+// RegisterStdioRelay to finish reading buffered stdout/stderr before Close
+// breaks the pipes. Must be called before relays are started.
+func (process *Process) SetStdioDrainTimeout(timeout time.Duration) {
+	process.stdioDrainTimeout = timeout
+}
+
+// RegisterStdioRelay registers an outstanding stdout/stderr relay with the
+// process. The returned function must be called (once) when the relay has
+// consumed all output.
+func (process *Process) RegisterStdioRelay() (done func()) {
+	process.stdioDrain.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(process.stdioDrain.Done)
+	}
+}
+
+// waitStdioDrain waits up to the configured drain timeout for registered
+// stdio relays to flush any remaining output.
+func (process *Process) waitStdioDrain(ctx context.Context) {
+	if process.stdioDrainTimeout == 0 {
+		return
+	}
+	drained := make(chan struct{})
+	go func() {
+		process.stdioDrain.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(process.stdioDrainTimeout):
+		log.G(ctx).Warning("timed out waiting for stdio relays to drain")
 	}
 }
 
@@ -107,7 +160,7 @@ func (process *Process) Signal(ctx context.Context, options interface{}) (bool,
 		return false, err
 	}
 
-	resultJSON, err := vmcompute.HcsSignalProcess(ctx, process.handle, string(optionsb))
+	resultJSON, err := process.system.vm.HcsSignalProcess(ctx, process.handle, string(optionsb))
 	events := processHcsResult(ctx, resultJSON)
 	delivered, err := process.processSignalResult(ctx, err)
 	if err != nil {
@@ -131,7 +184,7 @@ func (process *Process) Kill(ctx context.Context) (bool, error) {
 		return false, makeProcessError(process, operation, ErrProcessAlreadyStopped, nil)
 	}
 
-	if process.killSignalDelivered {
+	if atomic.LoadInt32(&process.killSignalDelivered) != 0 {
 		// A kill signal has already been sent to this process. Sending a second
 		// one offers no real benefit, as processes cannot stop themselves from
 		// being terminated, once a TerminateProcess has been issued. Sending a
@@ -172,7 +225,7 @@ func (process *Process) Kill(ctx context.Context) (bool, error) {
 	}
 	defer newProcessHandle.Close()
 
-	resultJSON, err := vmcompute.HcsTerminateProcess(ctx, newProcessHandle.handle)
+	resultJSON, err := newProcessHandle.system.vm.HcsTerminateProcess(ctx, newProcessHandle.handle)
 	if err != nil {
 		// We still need to check these two cases, as processes may still be killed by an
 		// external actor (human operator, OOM, random script etc).
@@ -201,7 +254,9 @@ func (process *Process) Kill(ctx context.Context) (bool, error) {
 		err = makeProcessError(newProcessHandle, operation, err, events)
 	}
 
-	process.killSignalDelivered = delivered
+	if delivered {
+		atomic.StoreInt32(&process.killSignalDelivered, 1)
+	}
 	return delivered, err
 }
 
@@ -235,7 +290,7 @@ func (process *Process) waitBackground() {
 
 		// Make sure we didn't race with Close() here
 		if process.handle != 0 {
-			propertiesJSON, resultJSON, err = vmcompute.HcsGetProcessProperties(ctx, process.handle)
+			propertiesJSON, resultJSON, err = process.system.vm.HcsGetProcessProperties(ctx, process.handle)
 			events := processHcsResult(ctx, resultJSON)
 			if err != nil {
 				err = makeProcessError(process, operation, err, events)
@@ -256,8 +311,13 @@ func (process *Process) waitBackground() {
 	}
 	log.G(ctx).WithField("exitCode", exitCode).Debug("process exited")
 
+	// Let any registered stdio relays finish flushing buffered output before
+	// the exit is surfaced and Close is allowed to break the pipes.
+	process.waitStdioDrain(ctx)
+
 	process.closedWaitOnce.Do(func() {
 		process.exitCode = exitCode
+		process.exitTime = time.Now()
 		process.waitError = err
 		close(process.waitBlock)
 	})
@@ -271,6 +331,27 @@ func (process *Process) Wait() error {
 	return process.waitError
 }
 
+// WaitChannel returns the wait channel of the process
+func (process *Process) WaitChannel() <-chan struct{} {
+	return process.waitBlock
+}
+
+// WaitError returns the wait error of the process.
+// It should only be called after the process has exited, ie. WaitChannel() is closed.
+func (process *Process) WaitError() error {
+	return process.waitError
+}
+
+// WaitCtx waits for the process to exit, or the context to be cancelled.
+func (process *Process) WaitCtx(ctx context.Context) error {
+	select {
+	case <-process.WaitChannel():
+		return process.WaitError()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Exited returns if the process has stopped
 func (process *Process) stopped() bool {
 	select {
@@ -304,7 +385,7 @@ func (process *Process) ResizeConsole(ctx context.Context, width, height uint16)
 		return err
 	}
 
-	resultJSON, err := vmcompute.HcsModifyProcess(ctx, process.handle, string(modifyRequestb))
+	resultJSON, err := process.system.vm.HcsModifyProcess(ctx, process.handle, string(modifyRequestb))
 	events := processHcsResult(ctx, resultJSON)
 	if err != nil {
 		return makeProcessError(process, operation, err, events)
@@ -325,6 +406,43 @@ func (process *Process) ExitCode() (int, error) {
 	return process.exitCode, nil
 }
 
+// ProcessExitStatus describes how a process exited, with richer detail than
+// the bare exit code.
+type ProcessExitStatus struct {
+	ExitCode int
+	// KilledBySignal reports whether the process was terminated by a signal:
+	// either a kill delivered through this Process, or (for LCOW) an exit
+	// code following the 128+signal convention.
+	KilledBySignal bool
+	// Signal is the terminating signal number when it can be derived from
+	// the exit code (LCOW only), else 0.
+	Signal int
+	// StartedAt and ExitedAt are wall-clock times observed by this Process
+	// object at creation and at delivery of the exit notification.
+	StartedAt time.Time
+	ExitedAt  time.Time
+}
+
+// ExitStatus returns a structured view of the process's exit. The process
+// must have already terminated.
+func (process *Process) ExitStatus() (*ProcessExitStatus, error) {
+	exitCode, err := process.ExitCode()
+	if err != nil {
+		return nil, err
+	}
+	status := &ProcessExitStatus{
+		ExitCode:       exitCode,
+		KilledBySignal: atomic.LoadInt32(&process.killSignalDelivered) != 0,
+		StartedAt:      process.startTime,
+		ExitedAt:       process.exitTime,
+	}
+	if process.system.OS() == "linux" && exitCode > 128 && exitCode < 128+64 {
+		status.KilledBySignal = true
+		status.Signal = exitCode - 128
+	}
+	return status, nil
+}
+
 // StdioLegacy returns the stdin, stdout, and stderr pipes, respectively. Closing
 // these pipes does not close the underlying pipes. Once returned, these pipes
 // are the responsibility of the caller to close.
@@ -353,7 +471,7 @@ func (process *Process) StdioLegacy() (_ io.WriteCloser, _ io.ReadCloser, _ io.R
 		return stdin, stdout, stderr, nil
 	}
 
-	processInfo, resultJSON, err := vmcompute.HcsGetProcessInfo(ctx, process.handle)
+	processInfo, resultJSON, err := process.system.vm.HcsGetProcessInfo(ctx, process.handle)
 	events := processHcsResult(ctx, resultJSON)
 	if err != nil {
 		return nil, nil, nil, makeProcessError(process, operation, err, events)
@@ -407,7 +525,7 @@ func (process *Process) CloseStdin(ctx context.Context) (err error) {
 			return err
 		}
 
-		resultJSON, err := vmcompute.HcsModifyProcess(ctx, process.handle, string(modifyRequestb))
+		resultJSON, err := process.system.vm.HcsModifyProcess(ctx, process.handle, string(modifyRequestb))
 		events := processHcsResult(ctx, resultJSON)
 		if err != nil {
 			return makeProcessError(process, operation, err, events)
@@ -491,6 +609,8 @@ func (process *Process) Close() (err error) {
 		return nil
 	}
 
+	process.waitStdioDrain(ctx)
+
 	process.stdioLock.Lock()
 	if process.stdin != nil {
 		process.stdin.Close()
@@ -510,11 +630,12 @@ func (process *Process) Close() (err error) {
 		return makeProcessError(process, operation, err, nil)
 	}
 
-	if err = vmcompute.HcsCloseProcess(ctx, process.handle); err != nil {
+	if err = process.system.vm.HcsCloseProcess(ctx, process.handle); err != nil {
 		return makeProcessError(process, operation, err, nil)
 	}
 
 	process.handle = 0
+	untrackProcess(process)
 	process.closedWaitOnce.Do(func() {
 		process.exitCode = -1
 		process.waitError = ErrAlreadyClosed
@@ -537,7 +658,7 @@ func (process *Process) registerCallback(ctx context.Context) error {
 	callbackMap[callbackNumber] = callbackContext
 	callbackMapLock.Unlock()
 
-	callbackHandle, err := vmcompute.HcsRegisterProcessCallback(ctx, process.handle, notificationWatcherCallback, callbackNumber)
+	callbackHandle, err := process.system.vm.HcsRegisterProcessCallback(ctx, process.handle, notificationWatcherCallback, callbackNumber)
 	if err != nil {
 		return err
 	}
@@ -566,7 +687,7 @@ func (process *Process) unregisterCallback(ctx context.Context) error {
 
 	// vmcompute.HcsUnregisterProcessCallback has its own synchronization to
 	// wait for all callbacks to complete. We must NOT hold the callbackMapLock.
-	err := vmcompute.HcsUnregisterProcessCallback(ctx, handle)
+	err := process.system.vm.HcsUnregisterProcessCallback(ctx, handle)
 	if err != nil {
 		return err
 	}
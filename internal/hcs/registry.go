@@ -0,0 +1,144 @@
+//go:build windows
+
+package hcs
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// The package tracks every open System and Process so that a long-running
+// shim can be inspected for handle leaks: ListTracked returns the live
+// objects with their handles, callback numbers and wait states, and
+// DebugHandler exposes the same snapshot over HTTP for a debug listener.
+
+// TrackedSystem is a point-in-time snapshot of an open System.
+type TrackedSystem struct {
+	ID             string  `json:"id"`
+	Handle         uintptr `json:"handle"`
+	CallbackNumber uintptr `json:"callbackNumber"`
+	WaitState      string  `json:"waitState"`
+}
+
+// TrackedProcess is a point-in-time snapshot of an open Process.
+type TrackedProcess struct {
+	SystemID       string  `json:"systemID"`
+	Pid            int     `json:"pid"`
+	Handle         uintptr `json:"handle"`
+	CallbackNumber uintptr `json:"callbackNumber"`
+	WaitState      string  `json:"waitState"`
+}
+
+const (
+	// waitStateWaiting means the background wait for the object is still
+	// outstanding, i.e. it has not exited and has not been closed.
+	waitStateWaiting = "waiting"
+	// waitStateCompleted means the wait has delivered: the object exited or
+	// its handle was closed.
+	waitStateCompleted = "completed"
+)
+
+var (
+	trackedMu        sync.Mutex
+	trackedSystems   = make(map[*System]struct{})
+	trackedProcesses = make(map[*Process]struct{})
+)
+
+func trackSystem(computeSystem *System) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+	trackedSystems[computeSystem] = struct{}{}
+}
+
+func untrackSystem(computeSystem *System) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+	delete(trackedSystems, computeSystem)
+}
+
+func trackProcess(process *Process) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+	trackedProcesses[process] = struct{}{}
+}
+
+func untrackProcess(process *Process) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+	delete(trackedProcesses, process)
+}
+
+func (computeSystem *System) waitState() string {
+	select {
+	case <-computeSystem.waitBlock:
+		return waitStateCompleted
+	default:
+		return waitStateWaiting
+	}
+}
+
+func (process *Process) waitState() string {
+	select {
+	case <-process.waitBlock:
+		return waitStateCompleted
+	default:
+		return waitStateWaiting
+	}
+}
+
+// ListTracked returns a snapshot of the Systems and Processes currently open
+// in this process.
+func ListTracked() ([]TrackedSystem, []TrackedProcess) {
+	trackedMu.Lock()
+	systems := make([]*System, 0, len(trackedSystems))
+	for s := range trackedSystems {
+		systems = append(systems, s)
+	}
+	processes := make([]*Process, 0, len(trackedProcesses))
+	for p := range trackedProcesses {
+		processes = append(processes, p)
+	}
+	trackedMu.Unlock()
+
+	// Snapshot the objects outside trackedMu so a stuck handleLock can't
+	// wedge tracking of other systems.
+	ts := make([]TrackedSystem, 0, len(systems))
+	for _, s := range systems {
+		s.handleLock.RLock()
+		ts = append(ts, TrackedSystem{
+			ID:             s.id,
+			Handle:         uintptr(s.handle),
+			CallbackNumber: s.callbackNumber,
+			WaitState:      s.waitState(),
+		})
+		s.handleLock.RUnlock()
+	}
+	tp := make([]TrackedProcess, 0, len(processes))
+	for _, p := range processes {
+		p.handleLock.RLock()
+		tp = append(tp, TrackedProcess{
+			SystemID:       p.SystemID(),
+			Pid:            p.processID,
+			Handle:         uintptr(p.handle),
+			CallbackNumber: p.callbackNumber,
+			WaitState:      p.waitState(),
+		})
+		p.handleLock.RUnlock()
+	}
+	return ts, tp
+}
+
+// DebugHandler returns an http.Handler that dumps the tracked Systems and
+// Processes as JSON. Callers can mount it on a debug listener (for example a
+// named pipe served with http.Serve) to inspect a running shim.
+func DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		systems, processes := ListTracked()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Systems   []TrackedSystem  `json:"systems"`
+			Processes []TrackedProcess `json:"processes"`
+		}{Systems: systems, Processes: processes})
+	})
+}
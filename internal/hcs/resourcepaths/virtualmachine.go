@@ -8,6 +8,9 @@ const (
 	CPUFrequencyPowerCapResourcePath string = "VirtualMachine/ComputeTopology/Processor/CpuFrequencyPowerCap"
 	CPULimitsResourcePath            string = "VirtualMachine/ComputeTopology/Processor/Limits"
 	SerialResourceFormat             string = "VirtualMachine/Devices/ComPorts/%d"
+	EnhancedModeVideoResourcePath    string = "VirtualMachine/Devices/EnhancedModeVideo"
+	KeyboardResourcePath             string = "VirtualMachine/Devices/Keyboard"
+	VideoMonitorResourcePath         string = "VirtualMachine/Devices/VideoMonitor"
 	FlexibleIovResourceFormat        string = "VirtualMachine/Devices/FlexibleIov/%s"
 	LicensingResourcePath            string = "VirtualMachine/Devices/Licensing"
 	MappedPipeResourceFormat         string = "VirtualMachine/Devices/MappedPipes/%s"
@@ -16,6 +19,7 @@ const (
 	SCSIResourceFormat               string = "VirtualMachine/Devices/Scsi/%s/Attachments/%d"
 	SharedMemoryRegionResourcePath   string = "VirtualMachine/Devices/SharedMemory/Regions"
 	VirtualPCIResourceFormat         string = "VirtualMachine/Devices/VirtualPci/%s"
+	VPMemControllerResourcePath      string = "VirtualMachine/Devices/VirtualPMem"
 	VPMemControllerResourceFormat    string = "VirtualMachine/Devices/VirtualPMem/Devices/%d"
 	VPMemDeviceResourceFormat        string = "VirtualMachine/Devices/VirtualPMem/Devices/%d/Mappings/%d"
 	VSMBShareResourcePath            string = "VirtualMachine/Devices/VirtualSmb/Shares"
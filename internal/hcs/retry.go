@@ -0,0 +1,117 @@
+//go:build windows
+
+package hcs
+
+import (
+	"context"
+	"math/rand"
+	"syscall"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+)
+
+var (
+	// ErrHcsServiceNotAvailable is an error encountered when the Host Compute Service
+	// is not available, typically while it is restarting.
+	ErrHcsServiceNotAvailable = syscall.Errno(0x803701b0)
+
+	// ErrRPCServerUnavailable is an error encountered when the RPC server backing the
+	// compute service cannot be reached (RPC_S_SERVER_UNAVAILABLE).
+	ErrRPCServerUnavailable = syscall.Errno(0x6ba)
+
+	// ErrRPCCallFailed is an error encountered when an RPC call to the compute service
+	// fails mid-flight, for example because the service is being restarted (RPC_S_CALL_FAILED).
+	ErrRPCCallFailed = syscall.Errno(0x6be)
+
+	// ErrRPCEndpointNotRegistered is an error encountered when the compute service has
+	// not (yet) registered its RPC endpoint with the endpoint mapper (EPT_S_NOT_REGISTERED).
+	ErrRPCEndpointNotRegistered = syscall.Errno(0x6d9)
+)
+
+// RetryPolicy controls how transient vmcompute failures are retried. Retries
+// wait Delay before the first retry and double the wait for each subsequent
+// one, capped at MaxDelay, with up to Jitter of each wait randomized to avoid
+// thundering herds on a busy host.
+type RetryPolicy struct {
+	// Attempts is the total number of times the operation is tried, including
+	// the initial attempt. Values below 2 disable retries.
+	Attempts int
+	// Delay is the wait before the first retry.
+	Delay time.Duration
+	// MaxDelay caps the exponentially growing wait between retries. Zero
+	// means no cap.
+	MaxDelay time.Duration
+	// Jitter is the fraction of each wait, in [0, 1], that is randomized.
+	Jitter float64
+}
+
+// Retry policies per operation class. These are variables so importers can
+// tune them for their environment before issuing calls.
+var (
+	// EnumerateRetryPolicy applies to compute system enumeration
+	// (GetComputeSystems and GetComputeSystemsV2).
+	EnumerateRetryPolicy = RetryPolicy{
+		Attempts: 3,
+		Delay:    100 * time.Millisecond,
+		MaxDelay: time.Second,
+		Jitter:   0.2,
+	}
+
+	// OpenRetryPolicy applies to opening a handle to an existing compute
+	// system.
+	OpenRetryPolicy = RetryPolicy{
+		Attempts: 3,
+		Delay:    100 * time.Millisecond,
+		MaxDelay: time.Second,
+		Jitter:   0.2,
+	}
+)
+
+// isTransientError returns true if err reports a failure that is expected to
+// clear on its own shortly: HCS mid-operation, the compute service not (yet)
+// available, or its RPC endpoint unreachable while the service restarts.
+func isTransientError(err error) bool {
+	return IsAny(err,
+		ErrVmcomputeOperationPending,
+		ErrVmcomputeOperationInvalidState,
+		ErrHcsServiceNotAvailable,
+		ErrRPCServerUnavailable,
+		ErrRPCCallFailed,
+		ErrRPCEndpointNotRegistered,
+	)
+}
+
+// runWithRetry runs f, retrying per policy while f returns a transient error.
+// It returns the last error from f, or ctx.Err() if the context is cancelled
+// while waiting to retry.
+func runWithRetry(ctx context.Context, policy RetryPolicy, operation string, f func() error) error {
+	delay := policy.Delay
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || !isTransientError(err) || attempt >= policy.Attempts {
+			return err
+		}
+		log.G(ctx).WithError(err).WithField("attempt", attempt).Infof("transient failure in %s, retrying", operation)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitteredDelay(delay, policy.Jitter)):
+		}
+		delay *= 2
+		if policy.MaxDelay != 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+}
+
+func jitteredDelay(delay time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 || delay <= 0 {
+		return delay
+	}
+	span := int64(float64(delay) * jitter)
+	if span <= 0 {
+		return delay
+	}
+	return delay + time.Duration(rand.Int63n(span))
+}
@@ -22,6 +22,10 @@ type Chipset struct {
 
 	UseUtc bool `json:"UseUtc,omitempty"`
 
+	// TimeSource configures the VM clock: "HostSynced" or "Independent".
+	// This is private in the schema. If regenerated need to put back.
+	TimeSource string `json:"TimeSource,omitempty"`
+
 	// LinuxKernelDirect - Added in v2.2 Builds >=181117
 	LinuxKernelDirect *LinuxKernelDirect `json:"LinuxKernelDirect,omitempty"`
 }
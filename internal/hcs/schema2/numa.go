@@ -0,0 +1,18 @@
+/*
+ * HCS API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 2.5
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package hcsschema
+
+type Numa struct {
+	VirtualNodeCount uint8 `json:"VirtualNodeCount,omitempty"`
+
+	PreferredPhysicalNodes []int64 `json:"PreferredPhysicalNodes,omitempty"`
+
+	Settings []NumaSetting `json:"Settings,omitempty"`
+}
@@ -43,4 +43,7 @@ type ProcessParameters struct {
 
 	//  if set, use the legacy console instead of conhost
 	UseLegacyConsole bool `json:"UseLegacyConsole,omitempty"`
+
+	//  optional priority class for the process, currently only supported for Windows
+	PriorityClass string `json:"PriorityClass,omitempty"`
 }
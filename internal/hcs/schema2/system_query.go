@@ -0,0 +1,22 @@
+/*
+ * HCS API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 2.1
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package hcsschema
+
+//  By default all compute systems are returned. This query provides a way to filter on
+//  identity and ownership properties.
+type SystemQuery struct {
+	Ids []string `json:"Ids,omitempty"`
+
+	Names []string `json:"Names,omitempty"`
+
+	Types []string `json:"Types,omitempty"`
+
+	Owners []string `json:"Owners,omitempty"`
+}
@@ -7,7 +7,6 @@ import (
 	"encoding/json"
 
 	hcsschema "github.com/Microsoft/hcsshim/internal/hcs/schema2"
-	"github.com/Microsoft/hcsshim/internal/vmcompute"
 )
 
 // GetServiceProperties returns properties of the host compute service.
@@ -18,7 +17,7 @@ func GetServiceProperties(ctx context.Context, q hcsschema.PropertyQuery) (*hcss
 	if err != nil {
 		return nil, err
 	}
-	propertiesJSON, resultJSON, err := vmcompute.HcsGetServiceProperties(ctx, string(queryb))
+	propertiesJSON, resultJSON, err := hcsClient.HcsGetServiceProperties(ctx, string(queryb))
 	events := processHcsResult(ctx, resultJSON)
 	if err != nil {
 		return nil, &HcsError{Op: operation, Err: err, Events: events}
@@ -42,7 +41,7 @@ func ModifyServiceSettings(ctx context.Context, settings hcsschema.ModificationR
 	if err != nil {
 		return err
 	}
-	resultJSON, err := vmcompute.HcsModifyServiceSettings(ctx, string(settingsJSON))
+	resultJSON, err := hcsClient.HcsModifyServiceSettings(ctx, string(settingsJSON))
 	events := processHcsResult(ctx, resultJSON)
 	if err != nil {
 		return &HcsError{Op: operation, Err: err, Events: events}
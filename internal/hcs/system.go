@@ -19,6 +19,9 @@ import (
 	"github.com/Microsoft/hcsshim/internal/log"
 	"github.com/Microsoft/hcsshim/internal/logfields"
 	"github.com/Microsoft/hcsshim/internal/oc"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestresource"
+	"github.com/Microsoft/hcsshim/internal/schemaversion"
 	"github.com/Microsoft/hcsshim/internal/timeout"
 	"github.com/Microsoft/hcsshim/internal/vmcompute"
 	"github.com/sirupsen/logrus"
@@ -28,6 +31,7 @@ import (
 type System struct {
 	handleLock     sync.RWMutex
 	handle         vmcompute.HcsSystem
+	vm             vmcompute.Client
 	id             string
 	callbackNumber uintptr
 
@@ -35,8 +39,10 @@ type System struct {
 	waitBlock      chan struct{}
 	waitError      error
 	exitError      error
+	crashReport    *CrashReport
 	os, typ, owner string
 	startTime      time.Time
+	timeouts       SystemTimeouts
 }
 
 var _ cow.Container = &System{}
@@ -45,17 +51,59 @@ var _ cow.ProcessHost = &System{}
 func newSystem(id string) *System {
 	return &System{
 		id:        id,
+		vm:        hcsClient,
 		waitBlock: make(chan struct{}),
 	}
 }
 
+// SystemTimeouts overrides the package-global timeout values for a single
+// System. Zero fields fall back to the corresponding value in the timeout
+// package.
+type SystemTimeouts struct {
+	Create time.Duration
+	Start  time.Duration
+	Pause  time.Duration
+	Resume time.Duration
+	Save   time.Duration
+}
+
+// SystemOption configures a System at create or open time.
+type SystemOption func(*System)
+
+// WithTimeouts overrides the operation timeouts for this System, so callers
+// in the same process can use different timeouts without changing the
+// timeout package globals.
+func WithTimeouts(t SystemTimeouts) SystemOption {
+	return func(computeSystem *System) {
+		computeSystem.timeouts = t
+	}
+}
+
+// WithVmcomputeClient overrides the vmcompute client used for this System's
+// operations, for tests and fault injection. The default is the real syscall
+// bindings.
+func WithVmcomputeClient(c vmcompute.Client) SystemOption {
+	return func(computeSystem *System) {
+		computeSystem.vm = c
+	}
+}
+
+// operationTimeout returns the effective timeout for an operation: the
+// per-System override if set, otherwise the package-global default.
+func (computeSystem *System) operationTimeout(override time.Duration, def *time.Duration) *time.Duration {
+	if override != 0 {
+		return &override
+	}
+	return def
+}
+
 // Implementation detail for silo naming, this should NOT be relied upon very heavily.
 func siloNameFmt(containerID string) string {
 	return fmt.Sprintf(`\Container_%s`, containerID)
 }
 
 // CreateComputeSystem creates a new compute system with the given configuration but does not start it.
-func CreateComputeSystem(ctx context.Context, id string, hcsDocumentInterface interface{}) (_ *System, err error) {
+func CreateComputeSystem(ctx context.Context, id string, hcsDocumentInterface interface{}, opts ...SystemOption) (_ *System, err error) {
 	operation := "hcs::CreateComputeSystem"
 
 	// hcsCreateComputeSystemContext is an async operation. Start the outer span
@@ -66,6 +114,9 @@ func CreateComputeSystem(ctx context.Context, id string, hcsDocumentInterface in
 	span.AddAttributes(trace.StringAttribute("cid", id))
 
 	computeSystem := newSystem(id)
+	for _, o := range opts {
+		o(computeSystem)
+	}
 
 	hcsDocumentB, err := json.Marshal(hcsDocumentInterface)
 	if err != nil {
@@ -79,7 +130,7 @@ func CreateComputeSystem(ctx context.Context, id string, hcsDocumentInterface in
 		resultJSON  string
 		createError error
 	)
-	computeSystem.handle, resultJSON, createError = vmcompute.HcsCreateComputeSystem(ctx, id, hcsDocument, identity)
+	computeSystem.handle, resultJSON, createError = computeSystem.vm.HcsCreateComputeSystem(ctx, id, hcsDocument, identity)
 	if createError == nil || IsPending(createError) {
 		defer func() {
 			if err != nil {
@@ -95,16 +146,19 @@ func CreateComputeSystem(ctx context.Context, id string, hcsDocumentInterface in
 	}
 
 	events, err := processAsyncHcsResult(ctx, createError, resultJSON, computeSystem.callbackNumber,
-		hcsNotificationSystemCreateCompleted, &timeout.SystemCreate)
+		hcsNotificationSystemCreateCompleted, computeSystem.operationTimeout(computeSystem.timeouts.Create, &timeout.SystemCreate))
 	if err != nil {
-		if errors.Is(err, ErrTimeout) {
-			// Terminate the compute system if it still exists. We're okay to
-			// ignore a failure here.
-			_ = computeSystem.Terminate(ctx)
+		if errors.Is(err, ErrTimeout) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			// Terminate the compute system if it still exists. Use a fresh
+			// context so the cleanup isn't abandoned by the same cancellation
+			// that interrupted the create. We're okay to ignore a failure
+			// here.
+			_ = computeSystem.Terminate(context.Background())
 		}
 		return nil, makeSystemError(computeSystem, operation, err, events)
 	}
 	go computeSystem.waitBackground()
+	trackSystem(computeSystem)
 	if err = computeSystem.getCachedProperties(ctx); err != nil {
 		return nil, err
 	}
@@ -112,12 +166,24 @@ func CreateComputeSystem(ctx context.Context, id string, hcsDocumentInterface in
 }
 
 // OpenComputeSystem opens an existing compute system by ID.
-func OpenComputeSystem(ctx context.Context, id string) (*System, error) {
+func OpenComputeSystem(ctx context.Context, id string, opts ...SystemOption) (*System, error) {
 	operation := "hcs::OpenComputeSystem"
 
 	computeSystem := newSystem(id)
-	handle, resultJSON, err := vmcompute.HcsOpenComputeSystem(ctx, id)
-	events := processHcsResult(ctx, resultJSON)
+	for _, o := range opts {
+		o(computeSystem)
+	}
+	var (
+		handle     vmcompute.HcsSystem
+		resultJSON string
+		events     []ErrorEvent
+	)
+	err := runWithRetry(ctx, OpenRetryPolicy, operation, func() error {
+		var err error //nolint:govet // shadow
+		handle, resultJSON, err = computeSystem.vm.HcsOpenComputeSystem(ctx, id)
+		events = processHcsResult(ctx, resultJSON)
+		return err
+	})
 	if err != nil {
 		return nil, makeSystemError(computeSystem, operation, err, events)
 	}
@@ -131,6 +197,7 @@ func OpenComputeSystem(ctx context.Context, id string) (*System, error) {
 		return nil, makeSystemError(computeSystem, operation, err, nil)
 	}
 	go computeSystem.waitBackground()
+	trackSystem(computeSystem)
 	if err = computeSystem.getCachedProperties(ctx); err != nil {
 		return nil, err
 	}
@@ -164,6 +231,11 @@ func (computeSystem *System) IsOCI() bool {
 	return computeSystem.os == "linux" && computeSystem.typ == "container"
 }
 
+// hcsClient is the vmcompute client used for package-level operations and as
+// the default for Systems created or opened by this package. Swappable for
+// testing and fault injection; see vmcompute.Client.
+var hcsClient vmcompute.Client = vmcompute.NewClient()
+
 // GetComputeSystems gets a list of the compute systems on the system that match the query
 func GetComputeSystems(ctx context.Context, q schema1.ComputeSystemQuery) ([]schema1.ContainerProperties, error) {
 	operation := "hcs::GetComputeSystems"
@@ -173,8 +245,16 @@ func GetComputeSystems(ctx context.Context, q schema1.ComputeSystemQuery) ([]sch
 		return nil, err
 	}
 
-	computeSystemsJSON, resultJSON, err := vmcompute.HcsEnumerateComputeSystems(ctx, string(queryb))
-	events := processHcsResult(ctx, resultJSON)
+	var (
+		computeSystemsJSON, resultJSON string
+		events                         []ErrorEvent
+	)
+	err = runWithRetry(ctx, EnumerateRetryPolicy, operation, func() error {
+		var err error //nolint:govet // shadow
+		computeSystemsJSON, resultJSON, err = hcsClient.HcsEnumerateComputeSystems(ctx, string(queryb))
+		events = processHcsResult(ctx, resultJSON)
+		return err
+	})
 	if err != nil {
 		return nil, &HcsError{Op: operation, Err: err, Events: events}
 	}
@@ -190,6 +270,43 @@ func GetComputeSystems(ctx context.Context, q schema1.ComputeSystemQuery) ([]sch
 	return computeSystems, nil
 }
 
+// GetComputeSystemsV2 gets a list of the compute systems on the system that
+// match the query, returning the schema2 property set. The query can filter on
+// system IDs, names, types and owners; an empty query returns all compute
+// systems.
+func GetComputeSystemsV2(ctx context.Context, q hcsschema.SystemQuery) ([]hcsschema.Properties, error) {
+	operation := "hcs::GetComputeSystemsV2"
+
+	queryb, err := json.Marshal(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		computeSystemsJSON, resultJSON string
+		events                         []ErrorEvent
+	)
+	err = runWithRetry(ctx, EnumerateRetryPolicy, operation, func() error {
+		var err error //nolint:govet // shadow
+		computeSystemsJSON, resultJSON, err = hcsClient.HcsEnumerateComputeSystems(ctx, string(queryb))
+		events = processHcsResult(ctx, resultJSON)
+		return err
+	})
+	if err != nil {
+		return nil, &HcsError{Op: operation, Err: err, Events: events}
+	}
+
+	if computeSystemsJSON == "" {
+		return nil, ErrUnexpectedValue
+	}
+	computeSystems := []hcsschema.Properties{}
+	if err = json.Unmarshal([]byte(computeSystemsJSON), &computeSystems); err != nil {
+		return nil, err
+	}
+
+	return computeSystems, nil
+}
+
 // Start synchronously starts the computeSystem.
 func (computeSystem *System) Start(ctx context.Context) (err error) {
 	operation := "hcs::System::Start"
@@ -210,9 +327,9 @@ func (computeSystem *System) Start(ctx context.Context) (err error) {
 		return makeSystemError(computeSystem, operation, ErrAlreadyClosed, nil)
 	}
 
-	resultJSON, err := vmcompute.HcsStartComputeSystem(ctx, computeSystem.handle, "")
+	resultJSON, err := computeSystem.vm.HcsStartComputeSystem(ctx, computeSystem.handle, "")
 	events, err := processAsyncHcsResult(ctx, err, resultJSON, computeSystem.callbackNumber,
-		hcsNotificationSystemStartCompleted, &timeout.SystemStart)
+		hcsNotificationSystemStartCompleted, computeSystem.operationTimeout(computeSystem.timeouts.Start, &timeout.SystemStart))
 	if err != nil {
 		return makeSystemError(computeSystem, operation, err, events)
 	}
@@ -236,7 +353,7 @@ func (computeSystem *System) Shutdown(ctx context.Context) error {
 		return nil
 	}
 
-	resultJSON, err := vmcompute.HcsShutdownComputeSystem(ctx, computeSystem.handle, "")
+	resultJSON, err := computeSystem.vm.HcsShutdownComputeSystem(ctx, computeSystem.handle, "")
 	events := processHcsResult(ctx, resultJSON)
 	switch err { //nolint:errorlint
 	case nil, ErrVmcomputeAlreadyStopped, ErrComputeSystemDoesNotExist, ErrVmcomputeOperationPending:
@@ -257,7 +374,7 @@ func (computeSystem *System) Terminate(ctx context.Context) error {
 		return nil
 	}
 
-	resultJSON, err := vmcompute.HcsTerminateComputeSystem(ctx, computeSystem.handle, "")
+	resultJSON, err := computeSystem.vm.HcsTerminateComputeSystem(ctx, computeSystem.handle, "")
 	events := processHcsResult(ctx, resultJSON)
 	switch err { //nolint:errorlint
 	case nil, ErrVmcomputeAlreadyStopped, ErrComputeSystemDoesNotExist, ErrVmcomputeOperationPending:
@@ -267,6 +384,54 @@ func (computeSystem *System) Terminate(ctx context.Context) error {
 	return nil
 }
 
+// ShutdownPath identifies which stop path brought the compute system down in
+// [System.ShutdownAndWait].
+type ShutdownPath string
+
+const (
+	// ShutdownPathGraceful indicates the system exited within the grace
+	// period after the shutdown request.
+	ShutdownPathGraceful ShutdownPath = "Graceful"
+	// ShutdownPathTerminated indicates the system had to be terminated,
+	// either because the grace period elapsed or because the shutdown
+	// request itself failed.
+	ShutdownPathTerminated ShutdownPath = "Terminated"
+)
+
+// ShutdownAndWait requests a graceful shutdown and waits up to `gracePeriod`
+// for the compute system to exit, escalating to Terminate (and waiting again,
+// bounded only by `ctx`) if it does not stop in time. It returns which path
+// brought the system down, along with the system's exit error (if any). A
+// system that already stopped reports ShutdownPathGraceful.
+func (computeSystem *System) ShutdownAndWait(ctx context.Context, gracePeriod time.Duration) (ShutdownPath, error) {
+	if err := computeSystem.Shutdown(ctx); err != nil {
+		// Shutdown already tolerates a system that has stopped or is
+		// stopping; a failure here means the guest could not be asked to
+		// stop, so go straight to Terminate.
+		log.G(ctx).WithError(err).Debug("graceful shutdown request failed; terminating")
+	} else {
+		timer := time.NewTimer(gracePeriod)
+		defer timer.Stop()
+		select {
+		case <-computeSystem.WaitChannel():
+			return ShutdownPathGraceful, computeSystem.WaitError()
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if err := computeSystem.Terminate(ctx); err != nil {
+		return "", err
+	}
+	select {
+	case <-computeSystem.WaitChannel():
+		return ShutdownPathTerminated, computeSystem.WaitError()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
 // waitBackground waits for the compute system exit notification. Once received
 // sets `computeSystem.waitError` (if any) and unblocks all `Wait` calls.
 //
@@ -289,6 +454,9 @@ func (computeSystem *System) waitBackground() {
 	default:
 		err = makeSystemError(computeSystem, operation, err, nil)
 	}
+	// Collect any guest crash report that arrived with (or before) the exit
+	// so it remains available after the callback is unregistered.
+	computeSystem.crashReport = computeSystem.fetchCrashReport(ctx)
 	computeSystem.closedWaitOnce.Do(func() {
 		computeSystem.waitError = err
 		close(computeSystem.waitBlock)
@@ -296,6 +464,58 @@ func (computeSystem *System) waitBackground() {
 	oc.SetSpanStatus(span, err)
 }
 
+// StartExitWatchdog starts a background poller that periodically queries the
+// compute system's properties as a fallback for the HCS exit notification,
+// which can be dropped when the host is under stress. If a poll observes that
+// the system has stopped before the notification arrives, the watchdog
+// synthesizes the exit, unblocking all `Wait` calls with an error wrapping
+// `ErrExitDetectedByPoll`. The synthesized exit cannot race with a legitimate
+// notification: whichever is delivered first wins and the other is discarded.
+//
+// The returned function stops the watchdog and is safe to call multiple times.
+func (computeSystem *System) StartExitWatchdog(interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go computeSystem.pollForExit(ctx, interval)
+	return cancel
+}
+
+func (computeSystem *System) pollForExit(ctx context.Context, interval time.Duration) {
+	operation := "hcs::System::pollForExit"
+	ctx, span := oc.StartSpan(ctx, operation)
+	defer span.End()
+	span.AddAttributes(trace.StringAttribute("cid", computeSystem.id))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-computeSystem.waitBlock:
+			// The notification (or an earlier poll) already delivered the exit.
+			return
+		case <-ticker.C:
+		}
+		props, err := computeSystem.Properties(ctx)
+		if err != nil {
+			if !IsNotExist(err) {
+				// Likely a transient query failure; try again on the next tick.
+				log.G(ctx).WithError(err).Debug("exit watchdog properties query failed")
+				continue
+			}
+			// The system is gone entirely, which also means it exited.
+		} else if !props.Stopped {
+			continue
+		}
+		computeSystem.closedWaitOnce.Do(func() {
+			log.G(ctx).Warn("system exit detected by poll; exit notification was not delivered")
+			computeSystem.waitError = makeSystemError(computeSystem, operation, ErrExitDetectedByPoll, nil)
+			close(computeSystem.waitBlock)
+		})
+		return
+	}
+}
+
 func (computeSystem *System) WaitChannel() <-chan struct{} {
 	return computeSystem.waitBlock
 }
@@ -359,7 +579,7 @@ func (computeSystem *System) Properties(ctx context.Context, types ...schema1.Pr
 		return nil, makeSystemError(computeSystem, operation, err, nil)
 	}
 
-	propertiesJSON, resultJSON, err := vmcompute.HcsGetComputeSystemProperties(ctx, computeSystem.handle, string(queryBytes))
+	propertiesJSON, resultJSON, err := computeSystem.vm.HcsGetComputeSystemProperties(ctx, computeSystem.handle, string(queryBytes))
 	events := processHcsResult(ctx, resultJSON)
 	if err != nil {
 		return nil, makeSystemError(computeSystem, operation, err, events)
@@ -376,6 +596,15 @@ func (computeSystem *System) Properties(ctx context.Context, types ...schema1.Pr
 	return properties, nil
 }
 
+// ProcessList returns the list of processes running in the compute system.
+func (computeSystem *System) ProcessList(ctx context.Context) ([]schema1.ProcessListItem, error) {
+	properties, err := computeSystem.Properties(ctx, schema1.PropertyTypeProcessList)
+	if err != nil {
+		return nil, err
+	}
+	return properties.ProcessList, nil
+}
+
 // queryInProc handles querying for container properties without reaching out to HCS. `props`
 // will be updated to contain any data returned from the queries present in `types`. If any properties
 // failed to be queried they will be tallied up and returned in as the first return value. Failures on
@@ -500,7 +729,7 @@ func (computeSystem *System) hcsPropertiesV2Query(ctx context.Context, types []h
 		return nil, makeSystemError(computeSystem, operation, err, nil)
 	}
 
-	propertiesJSON, resultJSON, err := vmcompute.HcsGetComputeSystemProperties(ctx, computeSystem.handle, string(queryBytes))
+	propertiesJSON, resultJSON, err := computeSystem.vm.HcsGetComputeSystemProperties(ctx, computeSystem.handle, string(queryBytes))
 	events := processHcsResult(ctx, resultJSON)
 	if err != nil {
 		return nil, makeSystemError(computeSystem, operation, err, events)
@@ -571,6 +800,72 @@ func (computeSystem *System) PropertiesV2(ctx context.Context, types ...hcsschem
 	return hcsProperties, nil
 }
 
+// PropertiesQuery describes a schema version aware property query for
+// QueryProperties. SchemaVersion selects which schema the query targets; a
+// nil SchemaVersion targets V2 with automatic fallback to V1 on hosts that
+// don't support the V2 query.
+type PropertiesQuery struct {
+	SchemaVersion *hcsschema.Version
+	// V1Types are the property types to request from a V1 query.
+	V1Types []schema1.PropertyType
+	// V2Types are the property types to request from a V2 query.
+	V2Types []hcsschema.PropertyType
+}
+
+// PropertiesResult is the result of a QueryProperties call. SchemaVersion
+// records which schema version actually answered the query; exactly one of
+// V1 or V2 is set, matching that version.
+type PropertiesResult struct {
+	SchemaVersion hcsschema.Version
+	// FellBack is true when the V2 query was unsupported on this host and
+	// the result was obtained by retrying with V1.
+	FellBack bool
+	V1       *schema1.ContainerProperties
+	V2       *hcsschema.Properties
+}
+
+// QueryProperties returns the requested compute system properties using the
+// schema version selected in `q`. When targeting V2 on a host that doesn't
+// support it, the query is automatically retried with V1 and the result is
+// flagged with FellBack so callers can tell which version answered.
+func (computeSystem *System) QueryProperties(ctx context.Context, q PropertiesQuery) (*PropertiesResult, error) {
+	if q.SchemaVersion != nil && schemaversion.IsV10(q.SchemaVersion) {
+		props, err := computeSystem.Properties(ctx, q.V1Types...)
+		if err != nil {
+			return nil, err
+		}
+		return &PropertiesResult{
+			SchemaVersion: *schemaversion.SchemaV10(),
+			V1:            props,
+		}, nil
+	}
+
+	props, err := computeSystem.PropertiesV2(ctx, q.V2Types...)
+	if err == nil {
+		return &PropertiesResult{
+			SchemaVersion: *schemaversion.SchemaV21(),
+			V2:            props,
+		}, nil
+	}
+	if !IsNotSupported(err) {
+		return nil, err
+	}
+
+	log.G(ctx).WithFields(logrus.Fields{
+		logfields.ContainerID: computeSystem.id,
+	}).WithError(err).Info("V2 property query unsupported, falling back to V1")
+
+	v1Props, v1Err := computeSystem.Properties(ctx, q.V1Types...)
+	if v1Err != nil {
+		return nil, v1Err
+	}
+	return &PropertiesResult{
+		SchemaVersion: *schemaversion.SchemaV10(),
+		FellBack:      true,
+		V1:            v1Props,
+	}, nil
+}
+
 // Pause pauses the execution of the computeSystem. This feature is not enabled in TP5.
 func (computeSystem *System) Pause(ctx context.Context) (err error) {
 	operation := "hcs::System::Pause"
@@ -589,9 +884,9 @@ func (computeSystem *System) Pause(ctx context.Context) (err error) {
 		return makeSystemError(computeSystem, operation, ErrAlreadyClosed, nil)
 	}
 
-	resultJSON, err := vmcompute.HcsPauseComputeSystem(ctx, computeSystem.handle, "")
+	resultJSON, err := computeSystem.vm.HcsPauseComputeSystem(ctx, computeSystem.handle, "")
 	events, err := processAsyncHcsResult(ctx, err, resultJSON, computeSystem.callbackNumber,
-		hcsNotificationSystemPauseCompleted, &timeout.SystemPause)
+		hcsNotificationSystemPauseCompleted, computeSystem.operationTimeout(computeSystem.timeouts.Pause, &timeout.SystemPause))
 	if err != nil {
 		return makeSystemError(computeSystem, operation, err, events)
 	}
@@ -617,9 +912,9 @@ func (computeSystem *System) Resume(ctx context.Context) (err error) {
 		return makeSystemError(computeSystem, operation, ErrAlreadyClosed, nil)
 	}
 
-	resultJSON, err := vmcompute.HcsResumeComputeSystem(ctx, computeSystem.handle, "")
+	resultJSON, err := computeSystem.vm.HcsResumeComputeSystem(ctx, computeSystem.handle, "")
 	events, err := processAsyncHcsResult(ctx, err, resultJSON, computeSystem.callbackNumber,
-		hcsNotificationSystemResumeCompleted, &timeout.SystemResume)
+		hcsNotificationSystemResumeCompleted, computeSystem.operationTimeout(computeSystem.timeouts.Resume, &timeout.SystemResume))
 	if err != nil {
 		return makeSystemError(computeSystem, operation, err, events)
 	}
@@ -627,6 +922,53 @@ func (computeSystem *System) Resume(ctx context.Context) (err error) {
 	return nil
 }
 
+// setFreeze asks the guest to freeze or thaw all container processes via a
+// guest modification request.
+func (computeSystem *System) setFreeze(ctx context.Context, operation string, frozen bool) error {
+	req := &hcsschema.ModifySettingRequest{
+		GuestRequest: guestrequest.ModificationRequest{
+			ResourceType: guestresource.ResourceTypeContainerFreeze,
+			RequestType:  guestrequest.RequestTypeUpdate,
+			Settings: guestresource.ContainerFreeze{
+				Frozen: frozen,
+			},
+		},
+	}
+	err := computeSystem.Modify(ctx, req)
+	if err != nil && IsNotSupported(err) {
+		return makeSystemError(computeSystem, operation, ErrNotSupported, nil)
+	}
+	return err
+}
+
+// Freeze quiesces all container processes in the compute system without
+// terminating them (the cgroup freezer on LCOW), so that a subsequent Save
+// captures a consistent snapshot. Returns ErrNotSupported where the guest
+// cannot freeze processes.
+func (computeSystem *System) Freeze(ctx context.Context) (err error) {
+	operation := "hcs::System::Freeze"
+
+	ctx, span := oc.StartSpan(ctx, operation)
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(trace.StringAttribute("cid", computeSystem.id))
+
+	return computeSystem.setFreeze(ctx, operation, true)
+}
+
+// Thaw resumes container processes previously quiesced by Freeze. Returns
+// ErrNotSupported where the guest cannot freeze processes.
+func (computeSystem *System) Thaw(ctx context.Context) (err error) {
+	operation := "hcs::System::Thaw"
+
+	ctx, span := oc.StartSpan(ctx, operation)
+	defer span.End()
+	defer func() { oc.SetSpanStatus(span, err) }()
+	span.AddAttributes(trace.StringAttribute("cid", computeSystem.id))
+
+	return computeSystem.setFreeze(ctx, operation, false)
+}
+
 // Save the compute system
 func (computeSystem *System) Save(ctx context.Context, options interface{}) (err error) {
 	operation := "hcs::System::Save"
@@ -650,9 +992,9 @@ func (computeSystem *System) Save(ctx context.Context, options interface{}) (err
 		return makeSystemError(computeSystem, operation, ErrAlreadyClosed, nil)
 	}
 
-	result, err := vmcompute.HcsSaveComputeSystem(ctx, computeSystem.handle, string(saveOptions))
+	result, err := computeSystem.vm.HcsSaveComputeSystem(ctx, computeSystem.handle, string(saveOptions))
 	events, err := processAsyncHcsResult(ctx, err, result, computeSystem.callbackNumber,
-		hcsNotificationSystemSaveCompleted, &timeout.SystemSave)
+		hcsNotificationSystemSaveCompleted, computeSystem.operationTimeout(computeSystem.timeouts.Save, &timeout.SystemSave))
 	if err != nil {
 		return makeSystemError(computeSystem, operation, err, events)
 	}
@@ -674,7 +1016,7 @@ func (computeSystem *System) createProcess(ctx context.Context, operation string
 	}
 
 	configuration := string(configurationb)
-	processInfo, processHandle, resultJSON, err := vmcompute.HcsCreateProcess(ctx, computeSystem.handle, configuration)
+	processInfo, processHandle, resultJSON, err := computeSystem.vm.HcsCreateProcess(ctx, computeSystem.handle, configuration)
 	events := processHcsResult(ctx, resultJSON)
 	if err != nil {
 		if v2, ok := c.(*hcsschema.ProcessParameters); ok {
@@ -730,7 +1072,7 @@ func (computeSystem *System) OpenProcess(ctx context.Context, pid int) (*Process
 		return nil, makeSystemError(computeSystem, operation, ErrAlreadyClosed, nil)
 	}
 
-	processHandle, resultJSON, err := vmcompute.HcsOpenProcess(ctx, computeSystem.handle, uint32(pid))
+	processHandle, resultJSON, err := computeSystem.vm.HcsOpenProcess(ctx, computeSystem.handle, uint32(pid))
 	events := processHcsResult(ctx, resultJSON)
 	if err != nil {
 		return nil, makeSystemError(computeSystem, operation, err, events)
@@ -773,12 +1115,13 @@ func (computeSystem *System) CloseCtx(ctx context.Context) (err error) {
 		return makeSystemError(computeSystem, operation, err, nil)
 	}
 
-	err = vmcompute.HcsCloseComputeSystem(ctx, computeSystem.handle)
+	err = computeSystem.vm.HcsCloseComputeSystem(ctx, computeSystem.handle)
 	if err != nil {
 		return makeSystemError(computeSystem, operation, err, nil)
 	}
 
 	computeSystem.handle = 0
+	untrackSystem(computeSystem)
 	computeSystem.closedWaitOnce.Do(func() {
 		computeSystem.waitError = ErrAlreadyClosed
 		close(computeSystem.waitBlock)
@@ -799,7 +1142,7 @@ func (computeSystem *System) registerCallback(ctx context.Context) error {
 	callbackMap[callbackNumber] = callbackContext
 	callbackMapLock.Unlock()
 
-	callbackHandle, err := vmcompute.HcsRegisterComputeSystemCallback(ctx, computeSystem.handle,
+	callbackHandle, err := computeSystem.vm.HcsRegisterComputeSystemCallback(ctx, computeSystem.handle,
 		notificationWatcherCallback, callbackNumber)
 	if err != nil {
 		return err
@@ -829,12 +1172,13 @@ func (computeSystem *System) unregisterCallback(ctx context.Context) error {
 
 	// hcsUnregisterComputeSystemCallback has its own synchronization
 	// to wait for all callbacks to complete. We must NOT hold the callbackMapLock.
-	err := vmcompute.HcsUnregisterComputeSystemCallback(ctx, handle)
+	err := computeSystem.vm.HcsUnregisterComputeSystemCallback(ctx, handle)
 	if err != nil {
 		return err
 	}
 
 	closeChannels(callbackContext.channels)
+	callbackContext.closeSubscribers()
 
 	callbackMapLock.Lock()
 	delete(callbackMap, callbackNumber)
@@ -862,7 +1206,7 @@ func (computeSystem *System) Modify(ctx context.Context, config interface{}) err
 	}
 
 	requestJSON := string(requestBytes)
-	resultJSON, err := vmcompute.HcsModifyComputeSystem(ctx, computeSystem.handle, requestJSON)
+	resultJSON, err := computeSystem.vm.HcsModifyComputeSystem(ctx, computeSystem.handle, requestJSON)
 	events := processHcsResult(ctx, resultJSON)
 	if err != nil {
 		return makeSystemError(computeSystem, operation, err, events)
@@ -0,0 +1,73 @@
+//go:build windows
+
+package hcs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/hcs/schema1"
+	"github.com/Microsoft/hcsshim/internal/vmcompute"
+)
+
+// enumerateFakeClient embeds vmcompute.Client so only the methods under test
+// need to be implemented; calling anything else panics on the nil interface.
+type enumerateFakeClient struct {
+	vmcompute.Client
+	enumerate func(ctx context.Context, query string) (string, string, error)
+}
+
+func (c *enumerateFakeClient) HcsEnumerateComputeSystems(ctx context.Context, query string) (string, string, error) {
+	return c.enumerate(ctx, query)
+}
+
+func TestGetComputeSystemsTransientRetry(t *testing.T) {
+	oldClient := hcsClient
+	oldPolicy := EnumerateRetryPolicy
+	defer func() {
+		hcsClient = oldClient
+		EnumerateRetryPolicy = oldPolicy
+	}()
+	EnumerateRetryPolicy.Delay = time.Millisecond
+
+	calls := 0
+	hcsClient = &enumerateFakeClient{enumerate: func(ctx context.Context, query string) (string, string, error) {
+		calls++
+		if calls == 1 {
+			return "", "", ErrVmcomputeOperationPending
+		}
+		return `[{"Id":"test"}]`, "", nil
+	}}
+
+	systems, err := GetComputeSystems(context.Background(), schema1.ComputeSystemQuery{})
+	if err != nil {
+		t.Fatalf("expected enumeration to succeed after transient failure, got: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 enumeration attempts, got %d", calls)
+	}
+	if len(systems) != 1 || systems[0].ID != "test" {
+		t.Errorf("unexpected enumeration result: %+v", systems)
+	}
+}
+
+func TestGetComputeSystemsNonTransientNoRetry(t *testing.T) {
+	oldClient := hcsClient
+	defer func() { hcsClient = oldClient }()
+
+	calls := 0
+	hcsClient = &enumerateFakeClient{enumerate: func(ctx context.Context, query string) (string, string, error) {
+		calls++
+		return "", "", ErrInvalidData
+	}}
+
+	_, err := GetComputeSystems(context.Background(), schema1.ComputeSystemQuery{})
+	if !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("expected ErrInvalidData, got: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single enumeration attempt for a non-transient error, got %d", calls)
+	}
+}
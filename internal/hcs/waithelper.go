@@ -78,5 +78,10 @@ func waitForNotification(
 		return ErrUnexpectedProcessAbort
 	case <-c:
 		return ErrTimeout
+	case <-ctx.Done():
+		// The wait is abandoned; a notification that fires later is absorbed
+		// by the channel's buffer and discarded when the callback is
+		// unregistered.
+		return ctx.Err()
 	}
 }
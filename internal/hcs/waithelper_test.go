@@ -0,0 +1,65 @@
+//go:build windows
+
+package hcs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// registerTestCallback installs a fake callback-map entry with system channels
+// so waitForNotification can be exercised without a real HCS handle.
+func registerTestCallback(t *testing.T) uintptr {
+	t.Helper()
+	callbackContext := &notificationWatcherContext{
+		channels: newSystemChannels(),
+		systemID: t.Name(),
+	}
+	callbackMapLock.Lock()
+	callbackNumber := nextCallback
+	nextCallback++
+	callbackMap[callbackNumber] = callbackContext
+	callbackMapLock.Unlock()
+	t.Cleanup(func() {
+		callbackMapLock.Lock()
+		closeChannels(callbackMap[callbackNumber].channels)
+		delete(callbackMap, callbackNumber)
+		callbackMapLock.Unlock()
+	})
+	return callbackNumber
+}
+
+func TestWaitForNotificationContextCancel(t *testing.T) {
+	callbackNumber := registerTestCallback(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- waitForNotification(ctx, callbackNumber, hcsNotificationSystemCreateCompleted, nil)
+	}()
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("waitForNotification did not unblock on context cancellation")
+	}
+}
+
+func TestWaitForNotificationDeliveredBeforeCancel(t *testing.T) {
+	callbackNumber := registerTestCallback(t)
+
+	callbackMapLock.RLock()
+	channels := callbackMap[callbackNumber].channels
+	callbackMapLock.RUnlock()
+	channels[hcsNotificationSystemCreateCompleted] <- nil
+
+	if err := waitForNotification(context.Background(), callbackNumber, hcsNotificationSystemCreateCompleted, nil); err != nil {
+		t.Fatalf("expected nil error for delivered notification, got %v", err)
+	}
+}
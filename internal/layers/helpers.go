@@ -48,6 +48,52 @@ func validateRootfsAndLayers(rootfs []*types.Mount, layerFolders []string) error
 	return nil
 }
 
+// ValidateLayerFolders checks that `folders` is ordered the way Create
+// expects: top-most read-only layer through base, followed by the writable
+// scratch location as the last entry. Each read-only layer folder must
+// contain expanded layer content (a `Files` directory), and the scratch
+// entry must be a writable directory that doesn't itself look like a
+// read-only layer. The returned error identifies the first folder that
+// doesn't fit its expected role, catching reversed orderings at create time
+// rather than as a mysterious mount failure.
+func ValidateLayerFolders(folders []string) error {
+	if len(folders) < 2 {
+		return fmt.Errorf("at least two layer folders are required (read-only layers followed by scratch): %w", errdefs.ErrFailedPrecondition)
+	}
+	for _, layer := range folders[:len(folders)-1] {
+		info, err := os.Stat(layer)
+		if err != nil {
+			return fmt.Errorf("read-only layer folder %s is not usable: %w", layer, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("read-only layer folder %s is not a directory", layer)
+		}
+		if _, err := os.Stat(filepath.Join(layer, "Files")); err != nil {
+			return fmt.Errorf("folder %s does not contain expanded layer content, expected in a read-only layer position: %w", layer, err)
+		}
+	}
+
+	scratch := folders[len(folders)-1]
+	info, err := os.Stat(scratch)
+	if err != nil {
+		return fmt.Errorf("scratch folder %s is not usable: %w", scratch, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("scratch folder %s is not a directory", scratch)
+	}
+	if _, err := os.Stat(filepath.Join(scratch, "Files")); err == nil {
+		return fmt.Errorf("folder %s contains read-only layer content but is in the scratch position, layer folders are likely in the wrong order", scratch)
+	}
+	f, err := os.CreateTemp(scratch, ".scratch-write-check-*")
+	if err != nil {
+		return fmt.Errorf("scratch folder %s is not writable: %w", scratch, err)
+	}
+	name := f.Name()
+	f.Close()
+	_ = os.Remove(name)
+	return nil
+}
+
 func ensureScratchVHD(ctx context.Context, scratchFolder string, layerFolders []string) error {
 	if _, err := os.Stat(scratchFolder); os.IsNotExist(err) {
 		if err := os.MkdirAll(scratchFolder, 0777); err != nil {
@@ -40,12 +40,14 @@ type LCOWLayers struct {
 
 type lcowLayersCloser struct {
 	uvm                     *uvm.UtilityVM
+	containerID             string
 	guestCombinedLayersPath string
 	scratchMount            resources.ResourceCloser
 	layerClosers            []resources.ResourceCloser
 }
 
 func (lc *lcowLayersCloser) Release(ctx context.Context) (retErr error) {
+	lc.uvm.UnregisterScratchMount(lc.containerID)
 	if err := lc.uvm.RemoveCombinedLayersLCOW(ctx, lc.guestCombinedLayersPath); err != nil {
 		log.G(ctx).WithError(err).Error("failed RemoveCombinedLayersLCOW")
 		if retErr == nil { //nolint:govet // nilness: consistency with below
@@ -161,8 +163,10 @@ func MountLCOWLayers(ctx context.Context, containerID string, layers *LCOWLayers
 		return "", "", nil, err
 	}
 	log.G(ctx).Debug("hcsshim::MountLCOWLayers Succeeded")
+	vm.RegisterScratchMount(containerID, scsiMount)
 	closer := &lcowLayersCloser{
 		uvm:                     vm,
+		containerID:             containerID,
 		guestCombinedLayersPath: rootfs,
 		scratchMount:            scsiMount,
 		layerClosers:            layerClosers,
@@ -186,6 +190,10 @@ func addLCOWLayer(ctx context.Context, vm *uvm.UtilityVM, layer *LCOWLayer) (uvm
 			return mount.GuestPath, mount, nil
 		} else if !errors.Is(err, uvm.ErrNoAvailableLocation) && !errors.Is(err, uvm.ErrMaxVPMemLayerSize) {
 			return "", nil, fmt.Errorf("failed to add VPMEM layer: %w", err)
+		} else if vm.NoVPMemFallback() {
+			// The UVM was configured to not fall back to SCSI, so surface the
+			// vPMEM exhaustion instead of silently changing attachment type.
+			return "", nil, fmt.Errorf("failed to add VPMEM layer: %w", err)
 		}
 	}
 
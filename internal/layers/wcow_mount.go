@@ -31,7 +31,7 @@ func MountWCOWLayers(ctx context.Context, containerID string, vm *uvm.UtilityVM,
 		if vm == nil {
 			return mountProcessIsolatedWCIFSLayers(ctx, l)
 		}
-		return mountHypervIsolatedWCIFSLayers(ctx, l, vm)
+		return mountHypervIsolatedWCIFSLayers(ctx, containerID, l, vm)
 	case *wcowForkedCIMLayers:
 		if vm == nil {
 			return mountProcessIsolatedForkedCimLayers(ctx, containerID, l)
@@ -261,12 +261,14 @@ func mountProcessIsolatedForkedCimLayers(ctx context.Context, containerID string
 
 type wcowIsolatedWCIFSLayerCloser struct {
 	uvm                     *uvm.UtilityVM
+	containerID             string
 	guestCombinedLayersPath string
 	scratchMount            resources.ResourceCloser
 	layerClosers            []resources.ResourceCloser
 }
 
 func (lc *wcowIsolatedWCIFSLayerCloser) Release(ctx context.Context) (retErr error) {
+	lc.uvm.UnregisterScratchMount(lc.containerID)
 	if err := lc.uvm.RemoveCombinedLayersWCOW(ctx, lc.guestCombinedLayersPath); err != nil {
 		log.G(ctx).WithError(err).Error("failed RemoveCombinedLayersWCOW")
 		if retErr == nil { //nolint:govet // nilness: consistency with below
@@ -293,7 +295,7 @@ func (lc *wcowIsolatedWCIFSLayerCloser) Release(ctx context.Context) (retErr err
 	return
 }
 
-func mountHypervIsolatedWCIFSLayers(ctx context.Context, l *wcowWCIFSLayers, vm *uvm.UtilityVM) (_ *MountedWCOWLayers, _ resources.ResourceCloser, err error) {
+func mountHypervIsolatedWCIFSLayers(ctx context.Context, containerID string, l *wcowWCIFSLayers, vm *uvm.UtilityVM) (_ *MountedWCOWLayers, _ resources.ResourceCloser, err error) {
 	log.G(ctx).WithField("os", vm.OS()).Debug("hcsshim::MountWCOWLayers V2 UVM")
 
 	// In some legacy layer use cases the scratch VHD might not be already created by the client
@@ -376,8 +378,11 @@ func mountHypervIsolatedWCIFSLayers(ctx context.Context, l *wcowWCIFSLayers, vm
 	}
 	log.G(ctx).Debug("hcsshim::MountWCOWLayers Succeeded")
 
+	vm.RegisterScratchMount(containerID, scsiMount)
+
 	return ml, &wcowIsolatedWCIFSLayerCloser{
 		uvm:                     vm,
+		containerID:             containerID,
 		guestCombinedLayersPath: ml.RootFS,
 		scratchMount:            scsiMount,
 		layerClosers:            layerClosers,
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"syscall"
 	"time"
 
 	"github.com/Microsoft/go-winio/vhd"
@@ -26,8 +27,67 @@ const (
 	// defaultVHDxBlockSizeMB is the block-size for the scratch VHDx's this
 	// package can create.
 	defaultVHDxBlockSizeMB = 1
+
+	// maxVHDxBlockSizeMB is the largest block-size VHDx supports.
+	maxVHDxBlockSizeMB = 256
 )
 
+// ScratchOpts tunes the geometry of the scratch VHDx's this package creates.
+type ScratchOpts struct {
+	// BlockSizeMB is the VHDx block size in MB. Zero means the package
+	// default (1MB); otherwise it must be a power of two no larger than
+	// 256MB. Larger blocks can improve sequential throughput at the cost
+	// of allocation granularity.
+	BlockSizeMB uint32
+	// FixedAllocation preallocates the full backing storage rather than
+	// growing the VHDx on demand, avoiding expansion stalls at runtime.
+	FixedAllocation bool
+}
+
+// isDefault returns true if `opts` requests the same geometry CreateScratch
+// would use without options, meaning a cached scratch is interchangeable.
+func (opts *ScratchOpts) isDefault() bool {
+	return opts == nil || (!opts.FixedAllocation && (opts.BlockSizeMB == 0 || opts.BlockSizeMB == defaultVHDxBlockSizeMB))
+}
+
+func (opts *ScratchOpts) validate() error {
+	if opts == nil || opts.BlockSizeMB == 0 {
+		return nil
+	}
+	if opts.BlockSizeMB > maxVHDxBlockSizeMB || opts.BlockSizeMB&(opts.BlockSizeMB-1) != 0 {
+		return fmt.Errorf("invalid scratch block size %dMB: must be a power of two no larger than %dMB", opts.BlockSizeMB, maxVHDxBlockSizeMB)
+	}
+	return nil
+}
+
+// createVhdx creates the scratch VHDx at `destFile` with the geometry
+// requested by `opts`.
+func createVhdx(destFile string, sizeGB uint32, opts *ScratchOpts) error {
+	blockSizeMB := uint32(defaultVHDxBlockSizeMB)
+	fixed := false
+	if opts != nil {
+		if opts.BlockSizeMB != 0 {
+			blockSizeMB = opts.BlockSizeMB
+		}
+		fixed = opts.FixedAllocation
+	}
+	if !fixed {
+		return vhd.CreateVhdx(destFile, sizeGB, blockSizeMB)
+	}
+	params := vhd.CreateVirtualDiskParameters{
+		Version: 2,
+		Version2: vhd.CreateVersion2{
+			MaximumSize:      uint64(sizeGB) * 1024 * 1024 * 1024,
+			BlockSizeInBytes: blockSizeMB * 1024 * 1024,
+		},
+	}
+	handle, err := vhd.CreateVirtualDisk(destFile, vhd.VirtualDiskAccessNone, vhd.CreateVirtualDiskFlagFullPhysicalAllocation, &params)
+	if err != nil {
+		return err
+	}
+	return syscall.CloseHandle(handle)
+}
+
 // CreateScratch uses a utility VM to create an empty scratch disk of a
 // requested size. It has a caching capability. If the cacheFile exists, and the
 // request is for a default size, a copy of that is made to the target. If the
@@ -35,6 +95,13 @@ const (
 // to create target. It is the responsibility of the caller to synchronize
 // simultaneous attempts to create the cache file.
 func CreateScratch(ctx context.Context, lcowUVM *uvm.UtilityVM, destFile string, sizeGB uint32, cacheFile string) error {
+	return CreateScratchWithOpts(ctx, lcowUVM, destFile, sizeGB, cacheFile, nil)
+}
+
+// CreateScratchWithOpts is CreateScratch with control over the scratch VHDx
+// geometry. The cache is only consulted (or seeded) for the default geometry,
+// since a cached scratch with different geometry is not interchangeable.
+func CreateScratchWithOpts(ctx context.Context, lcowUVM *uvm.UtilityVM, destFile string, sizeGB uint32, cacheFile string, opts *ScratchOpts) error {
 	if lcowUVM == nil {
 		return fmt.Errorf("no uvm")
 	}
@@ -43,14 +110,18 @@ func CreateScratch(ctx context.Context, lcowUVM *uvm.UtilityVM, destFile string,
 		return errors.New("lcow::CreateScratch requires a linux utility VM to operate")
 	}
 
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
 	log.G(ctx).WithFields(logrus.Fields{
 		"dest":   destFile,
 		"sizeGB": sizeGB,
 		"cache":  cacheFile,
 	}).Debug("lcow::CreateScratch opts")
 
-	// Retrieve from cache if the default size and already on disk
-	if cacheFile != "" && sizeGB == DefaultScratchSizeGB {
+	// Retrieve from cache if the default size and geometry and already on disk
+	if cacheFile != "" && sizeGB == DefaultScratchSizeGB && opts.isDefault() {
 		if _, err := os.Stat(cacheFile); err == nil {
 			if err := copyfile.CopyFile(ctx, cacheFile, destFile, false); err != nil {
 				return fmt.Errorf("failed to copy cached file '%s' to '%s': %w", cacheFile, destFile, err)
@@ -64,7 +135,7 @@ func CreateScratch(ctx context.Context, lcowUVM *uvm.UtilityVM, destFile string,
 	}
 
 	// Create the VHDX
-	if err := vhd.CreateVhdx(destFile, sizeGB, defaultVHDxBlockSizeMB); err != nil {
+	if err := createVhdx(destFile, sizeGB, opts); err != nil {
 		return fmt.Errorf("failed to create VHDx %s: %w", destFile, err)
 	}
 
@@ -140,7 +211,7 @@ func CreateScratch(ctx context.Context, lcowUVM *uvm.UtilityVM, destFile string,
 	}
 
 	// Populate the cache.
-	if cacheFile != "" && (sizeGB == DefaultScratchSizeGB) {
+	if cacheFile != "" && sizeGB == DefaultScratchSizeGB && opts.isDefault() {
 		if err := copyfile.CopyFile(ctx, destFile, cacheFile, true); err != nil {
 			return fmt.Errorf("failed to seed cache '%s' from '%s': %w", destFile, cacheFile, err)
 		}
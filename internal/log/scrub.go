@@ -2,6 +2,7 @@ package log
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"sync/atomic"
@@ -40,12 +41,37 @@ func IsScrubbingEnabled() bool {
 	return v != 0
 }
 
+type sensitiveEnvContextKeyType int
+
+const _sensitiveEnvContextKey sensitiveEnvContextKeyType = iota
+
+// WithSensitiveEnvironment marks the context so that process documents logged
+// under it have their Environment scrubbed, even when scrubbing is not
+// globally enabled. Use this when a process's environment carries secrets or
+// tokens. The marker stays host-side; nothing is added to the documents
+// themselves.
+func WithSensitiveEnvironment(ctx context.Context) context.Context {
+	return context.WithValue(ctx, _sensitiveEnvContextKey, true)
+}
+
+// IsEnvironmentSensitive reports whether the context was marked with
+// WithSensitiveEnvironment.
+func IsEnvironmentSensitive(ctx context.Context) bool {
+	b, _ := ctx.Value(_sensitiveEnvContextKey).(bool)
+	return b
+}
+
 // ScrubProcessParameters scrubs HCS Create Process requests with config parameters of
-// type internal/hcs/schema2.ScrubProcessParameters (aka hcsshema.ScrubProcessParameters)
-func ScrubProcessParameters(s string) (string, error) {
+// type internal/hcs/schema2.ScrubProcessParameters (aka hcsshema.ScrubProcessParameters).
+// The environment is scrubbed when scrubbing is globally enabled or when `ctx`
+// is marked with WithSensitiveEnvironment.
+func ScrubProcessParameters(ctx context.Context, s string) (string, error) {
+	if !IsScrubbingEnabled() && !IsEnvironmentSensitive(ctx) {
+		return s, nil
+	}
 	// todo: deal with v1 ProcessConfig
 	b := []byte(s)
-	if !IsScrubbingEnabled() || !hasKeywords(b) || !json.Valid(b) {
+	if !hasKeywords(b) || !json.Valid(b) {
 		return s, nil
 	}
 
@@ -104,12 +130,16 @@ func scrubLinuxHostedSystem(m genMap) error {
 }
 
 // ScrubBridgeExecProcess scrubs requests sent over the bridge of type
-// internal/gcs/protocol.containerExecuteProcess
-func ScrubBridgeExecProcess(b []byte) ([]byte, error) {
-	return scrubBytes(b, scrubExecuteProcess)
+// internal/gcs/protocol.containerExecuteProcess. The environment is scrubbed
+// when scrubbing is globally enabled or when `ctx` is marked with
+// WithSensitiveEnvironment.
+func ScrubBridgeExecProcess(ctx context.Context, b []byte) ([]byte, error) {
+	return scrubBytes(b, func(m genMap) error {
+		return scrubExecuteProcess(ctx, m)
+	})
 }
 
-func scrubExecuteProcess(m genMap) error {
+func scrubExecuteProcess(ctx context.Context, m genMap) error {
 	if !isRequestBase(m) {
 		return ErrUnknownType
 	}
@@ -121,7 +151,7 @@ func scrubExecuteProcess(m genMap) error {
 				return ErrUnknownType
 			}
 
-			s, err := ScrubProcessParameters(s)
+			s, err := ScrubProcessParameters(ctx, s)
 			if err != nil {
 				return err
 			}
@@ -133,6 +163,61 @@ func scrubExecuteProcess(m genMap) error {
 	return ErrUnknownType
 }
 
+// _redactedFields are field names whose values are replaced wholesale when
+// scrubbing HCS documents: process environment blocks, CCG credential
+// specs, and HvSocket security descriptors.
+var _redactedFields = map[string]struct{}{
+	"Environment":        {},
+	"Credentials":        {},
+	"CredentialSpec":     {},
+	"SecurityDescriptor": {},
+}
+
+// ScrubComputeSystemDocument scrubs an HCS compute system document (create or
+// modify settings) of sensitive fields before it is logged. The input is
+// returned unchanged when scrubbing is disabled or the document cannot be
+// parsed, so logging never fails on account of scrubbing.
+func ScrubComputeSystemDocument(s string) string {
+	if !IsScrubbingEnabled() {
+		return s
+	}
+	b := []byte(s)
+	if !json.Valid(b) {
+		return s
+	}
+	m := make(genMap)
+	if err := json.Unmarshal(b, &m); err != nil {
+		return s
+	}
+	redactFields(m)
+	b, err := encode(m)
+	if err != nil {
+		return s
+	}
+	return string(b)
+}
+
+// redactFields recursively replaces the values of the fields named in
+// _redactedFields throughout the document.
+func redactFields(m genMap) {
+	for k, v := range m {
+		if _, ok := _redactedFields[k]; ok {
+			m[k] = _scrubbedReplacement
+			continue
+		}
+		switch val := v.(type) {
+		case map[string]interface{}:
+			redactFields(val)
+		case []interface{}:
+			for _, item := range val {
+				if sub, ok := item.(map[string]interface{}); ok {
+					redactFields(sub)
+				}
+			}
+		}
+	}
+}
+
 func scrubBytes(b []byte, scrub scrubberFunc) ([]byte, error) {
 	if !IsScrubbingEnabled() || !hasKeywords(b) || !json.Valid(b) {
 		return b, nil
@@ -4,6 +4,9 @@ type Endpoint struct {
 	EndpointName string
 	NamespaceID  string
 	Settings     *EndpointSettings
+	// Metadata holds additional key/value data (CNI results, pod labels)
+	// stashed alongside the endpoint record.
+	Metadata map[string]string
 }
 
 type EndpointSettings struct {
@@ -11,9 +14,13 @@ type EndpointSettings struct {
 	Macaddress            string
 	IPAddress             string
 	IPAddressPrefixLength uint32
-	NetworkName           string
-	DefaultGateway        string
-	DeviceDetails         *DeviceDetails
+	// IPv6 configuration for dual-stack endpoints; empty for v4-only.
+	IPv6Address        string
+	IPv6PrefixLength   uint32
+	IPv6DefaultGateway string
+	NetworkName        string
+	DefaultGateway     string
+	DeviceDetails      *DeviceDetails
 }
 
 type DeviceDetails struct {
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Backup streams a consistent snapshot of the database to `w` while the
+// service keeps running, returning the number of bytes written. The snapshot
+// is taken in a single read transaction, so writes that land during the copy
+// are not included.
+func Backup(ctx context.Context, db *bolt.DB, w io.Writer) (int64, error) {
+	var n int64
+	if err := db.View(func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Restore writes a snapshot produced by Backup to the database file at
+// `path`, validating it before atomically replacing any existing file. The
+// database at `path` must not be open.
+func Restore(ctx context.Context, r io.Reader, path string) error {
+	tmp := path + ".restore"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return errors.Wrap(err, "failed to write database snapshot")
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	// Make sure the snapshot is a usable database before replacing anything.
+	db, err := bolt.Open(tmp, 0600, &bolt.Options{ReadOnly: true})
+	if err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "database snapshot failed validation")
+	}
+	if err := db.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Compact rewrites the database file at `path`, reclaiming space left behind
+// by deleted records, and atomically replaces the original. The database must
+// not be open.
+func Compact(ctx context.Context, path string) error {
+	src, err := bolt.Open(path, 0600, &bolt.Options{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".compact"
+	dst, err := bolt.Open(tmp, 0600, nil)
+	if err != nil {
+		src.Close()
+		return err
+	}
+	err = src.View(func(srcTx *bolt.Tx) error {
+		return dst.Update(func(dstTx *bolt.Tx) error {
+			return srcTx.ForEach(func(name []byte, b *bolt.Bucket) error {
+				dstBkt, err := dstTx.CreateBucketIfNotExists(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(b, dstBkt)
+			})
+		})
+	})
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := src.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func copyBucket(src, dst *bolt.Bucket) error {
+	return src.ForEach(func(k, v []byte) error {
+		if v == nil {
+			dstSub, err := dst.CreateBucketIfNotExists(k)
+			if err != nil {
+				return err
+			}
+			return copyBucket(src.Bucket(k), dstSub)
+		}
+		return dst.Put(k, v)
+	})
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewComputeAgentStore(db)
+	containerID := "fake-container-id"
+	address := "123412341234"
+	if err := store.UpdateComputeAgent(ctx, containerID, address); err != nil {
+		t.Fatal(err)
+	}
+
+	var snapshot bytes.Buffer
+	n, err := Backup(ctx, db, &snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(snapshot.Len()) {
+		t.Fatalf("expected %d bytes written, got %d", snapshot.Len(), n)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	restoredPath := filepath.Join(tempDir, "networkproxy.db.restored")
+	if err := Restore(ctx, &snapshot, restoredPath); err != nil {
+		t.Fatal(err)
+	}
+
+	restoredDB, err := bolt.Open(restoredPath, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restoredDB.Close()
+
+	restored := NewComputeAgentStore(restoredDB)
+	actual, err := restored.GetComputeAgent(ctx, containerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != address {
+		t.Fatalf("expected restored address %v, got %v", address, actual)
+	}
+}
+
+func TestRestore_InvalidSnapshot(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	path := filepath.Join(tempDir, "networkproxy.db.test")
+	if err := Restore(ctx, bytes.NewBufferString("not a database"), path); err == nil {
+		t.Fatal("expected restoring an invalid snapshot to fail")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	path := filepath.Join(tempDir, "networkproxy.db.test")
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewComputeAgentStore(db)
+	if err := store.UpdateComputeAgent(ctx, "fake-container-id", "123412341234"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpdateComputeAgent(ctx, "fake-container-id-2", "234523452345"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteComputeAgent(ctx, "fake-container-id-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Compact(ctx, path); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store = NewComputeAgentStore(db)
+	actual, err := store.GetComputeAgent(ctx, "fake-container-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != "123412341234" {
+		t.Fatalf("unexpected address %v after compaction", actual)
+	}
+	if _, err := store.GetComputeAgent(ctx, "fake-container-id-2"); err == nil {
+		t.Fatal("expected the deleted entry to stay deleted after compaction")
+	}
+}
@@ -9,14 +9,19 @@ const schemaVersion = "v1"
 var (
 	bucketKeyVersion = []byte(schemaVersion)
 
-	bucketKeyNetwork      = []byte("network")
-	bucketKeyEndpoint     = []byte("endpoint")
-	bucketKeyComputeAgent = []byte("computeagent")
+	bucketKeyNetwork           = []byte("network")
+	bucketKeyEndpoint          = []byte("endpoint")
+	bucketKeyComputeAgent      = []byte("computeagent")
+	bucketKeyComputeAgentLease = []byte("computeagentlease")
+	bucketKeyMACPool           = []byte("macpool")
+	bucketKeyIPAM              = []byte("ipam")
 )
 
 // Below is the current database schema. This should be updated any time the schema is
 // changed or updated. The version should be incremented if breaking changes are made.
 //  └──v1                                        - Schema version bucket
+//     └──metadata                               - Store metadata bucket
+//			└──schemaVersion : <int>             - Record schema version, see migrate.go
 //     └──computeagent							 - Compute agent bucket
 //			└──containerID : <string>            - Entry in compute agent bucket: Address to
 //												   the compute agent for containerID
@@ -75,3 +80,27 @@ func createComputeAgentBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
 func getComputeAgentBucket(tx *bolt.Tx) *bolt.Bucket {
 	return getBucket(tx, bucketKeyVersion, bucketKeyComputeAgent)
 }
+
+func createComputeAgentLeaseBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	return createBucketIfNotExists(tx, bucketKeyVersion, bucketKeyComputeAgentLease)
+}
+
+func createMACPoolBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	return createBucketIfNotExists(tx, bucketKeyVersion, bucketKeyMACPool)
+}
+
+func getMACPoolBucket(tx *bolt.Tx) *bolt.Bucket {
+	return getBucket(tx, bucketKeyVersion, bucketKeyMACPool)
+}
+
+func createIPAMBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	return createBucketIfNotExists(tx, bucketKeyVersion, bucketKeyIPAM)
+}
+
+func getIPAMBucket(tx *bolt.Tx) *bolt.Bucket {
+	return getBucket(tx, bucketKeyVersion, bucketKeyIPAM)
+}
+
+func getComputeAgentLeaseBucket(tx *bolt.Tx) *bolt.Bucket {
+	return getBucket(tx, bucketKeyVersion, bucketKeyComputeAgentLease)
+}
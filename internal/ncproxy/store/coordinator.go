@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+
+	ncproxynetworking "github.com/Microsoft/hcsshim/internal/ncproxy/networking"
+	"github.com/pkg/errors"
+)
+
+// PodNetworkingSpec describes the state EnsurePodNetworking brings into
+// existence: a network, an endpoint on that network, and optionally the
+// compute agent address registered for the pod's container.
+type PodNetworkingSpec struct {
+	Network  *ncproxynetworking.Network
+	Endpoint *ncproxynetworking.Endpoint
+	// ContainerID and ComputeAgentAddress record the compute agent serving
+	// the pod. Both may be empty to skip compute agent registration.
+	ContainerID         string
+	ComputeAgentAddress string
+}
+
+// Coordinator sequences multi-step updates that span the networking and
+// compute agent stores. The stores may be backed by separate database files,
+// so cross-store atomicity is not possible; instead the coordinator applies
+// updates in a consistent order (network, endpoint, compute agent) and rolls
+// back the earlier steps it performed when a later one fails, so an error
+// leaves no partial state behind.
+type Coordinator struct {
+	networking   *NetworkingStore
+	computeAgent *ComputeAgentStore
+}
+
+func NewCoordinator(networking *NetworkingStore, computeAgent *ComputeAgentStore) *Coordinator {
+	return &Coordinator{
+		networking:   networking,
+		computeAgent: computeAgent,
+	}
+}
+
+// isNotFoundError returns true for the store errors that indicate a record
+// simply doesn't exist yet.
+func isNotFoundError(err error) bool {
+	return errors.Is(err, ErrKeyNotFound) || errors.Is(err, ErrBucketNotFound)
+}
+
+// EnsurePodNetworking ensures `spec.Network` exists, creates
+// `spec.Endpoint` on it and records the compute agent address for
+// `spec.ContainerID`. Steps that already hold the desired state are skipped,
+// and on failure any state created by this call is removed before
+// returning.
+func (c *Coordinator) EnsurePodNetworking(ctx context.Context, spec PodNetworkingSpec) (err error) {
+	if spec.Network == nil || spec.Network.NetworkName == "" {
+		return errors.New("a network with a name must be specified")
+	}
+	if spec.Endpoint == nil || spec.Endpoint.EndpointName == "" {
+		return errors.New("an endpoint with a name must be specified")
+	}
+	if (spec.ContainerID == "") != (spec.ComputeAgentAddress == "") {
+		return errors.New("container ID and compute agent address must be specified together")
+	}
+
+	createdNetwork := false
+	if _, getErr := c.networking.GetNetworkByName(ctx, spec.Network.NetworkName); getErr != nil {
+		if !isNotFoundError(getErr) {
+			return getErr
+		}
+		if err := c.networking.CreateNetwork(ctx, spec.Network); err != nil {
+			return errors.Wrapf(err, "failed to create network %v", spec.Network.NetworkName)
+		}
+		createdNetwork = true
+	}
+	defer func() {
+		if err != nil && createdNetwork {
+			if dErr := c.networking.DeleteNetwork(ctx, spec.Network.NetworkName, false); dErr != nil {
+				err = errors.Wrapf(err, "failed to roll back network %v: %v", spec.Network.NetworkName, dErr)
+			}
+		}
+	}()
+
+	createdEndpoint := false
+	if _, getErr := c.networking.GetEndpointByName(ctx, spec.Endpoint.EndpointName); getErr != nil {
+		if !isNotFoundError(getErr) {
+			return getErr
+		}
+		if err := c.networking.CreatEndpoint(ctx, spec.Endpoint); err != nil {
+			return errors.Wrapf(err, "failed to create endpoint %v", spec.Endpoint.EndpointName)
+		}
+		createdEndpoint = true
+	}
+	defer func() {
+		if err != nil && createdEndpoint {
+			if dErr := c.networking.DeleteEndpoint(ctx, spec.Endpoint.EndpointName); dErr != nil {
+				err = errors.Wrapf(err, "failed to roll back endpoint %v: %v", spec.Endpoint.EndpointName, dErr)
+			}
+		}
+	}()
+
+	if spec.ContainerID != "" {
+		if err := c.computeAgent.UpdateComputeAgent(ctx, spec.ContainerID, spec.ComputeAgentAddress); err != nil {
+			return errors.Wrapf(err, "failed to record compute agent address for %v", spec.ContainerID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	ncproxynetworking "github.com/Microsoft/hcsshim/internal/ncproxy/networking"
+	bolt "go.etcd.io/bbolt"
+)
+
+func testPodNetworkingSpec() PodNetworkingSpec {
+	return PodNetworkingSpec{
+		Network: &ncproxynetworking.Network{
+			NetworkName: "test-network-name",
+			Settings:    &ncproxynetworking.NetworkSettings{Name: "test-network-name"},
+		},
+		Endpoint: &ncproxynetworking.Endpoint{
+			EndpointName: "test-endpoint-name",
+			Settings:     &ncproxynetworking.EndpointSettings{Name: "test-endpoint-name", NetworkName: "test-network-name"},
+		},
+		ContainerID:         "fake-container-id",
+		ComputeAgentAddress: "123412341234",
+	}
+}
+
+func TestCoordinatorEnsurePodNetworking(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	networkingStore := NewNetworkingStore(db)
+	computeAgentStore := NewComputeAgentStore(db)
+	coordinator := NewCoordinator(networkingStore, computeAgentStore)
+
+	spec := testPodNetworkingSpec()
+	if err := coordinator.EnsurePodNetworking(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := networkingStore.GetNetworkByName(ctx, spec.Network.NetworkName); err != nil {
+		t.Fatalf("expected network to exist: %v", err)
+	}
+	if _, err := networkingStore.GetEndpointByName(ctx, spec.Endpoint.EndpointName); err != nil {
+		t.Fatalf("expected endpoint to exist: %v", err)
+	}
+	address, err := computeAgentStore.GetComputeAgent(ctx, spec.ContainerID)
+	if err != nil {
+		t.Fatalf("expected compute agent address to exist: %v", err)
+	}
+	if address != spec.ComputeAgentAddress {
+		t.Fatalf("compute agent addresses are not equal, expected %v but got %v", spec.ComputeAgentAddress, address)
+	}
+
+	// Ensuring again against existing state should succeed.
+	if err := coordinator.EnsurePodNetworking(ctx, spec); err != nil {
+		t.Fatalf("expected ensure to be idempotent: %v", err)
+	}
+}
+
+func TestCoordinatorEnsurePodNetworkingRollback(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	networkingDB, err := bolt.Open(filepath.Join(tempDir, "networking.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer networkingDB.Close()
+
+	computeAgentDB, err := bolt.Open(filepath.Join(tempDir, "computeagent.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	networkingStore := NewNetworkingStore(networkingDB)
+	computeAgentStore := NewComputeAgentStore(computeAgentDB)
+	coordinator := NewCoordinator(networkingStore, computeAgentStore)
+
+	// Force the final compute agent step to fail so the earlier steps are
+	// rolled back.
+	if err := computeAgentDB.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := testPodNetworkingSpec()
+	if err := coordinator.EnsurePodNetworking(ctx, spec); err == nil {
+		t.Fatal("expected ensure to fail when compute agent store is unavailable")
+	}
+
+	if _, err := networkingStore.GetNetworkByName(ctx, spec.Network.NetworkName); err == nil {
+		t.Fatal("expected network to have been rolled back")
+	}
+	if _, err := networkingStore.GetEndpointByName(ctx, spec.Endpoint.EndpointName); err == nil {
+		t.Fatal("expected endpoint to have been rolled back")
+	}
+}
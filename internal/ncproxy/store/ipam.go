@@ -0,0 +1,211 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// ErrSubnetExhausted is returned by AllocateIP when every usable address
+	// in the subnet is assigned.
+	ErrSubnetExhausted = errors.New("no free IP addresses in subnet")
+	// ErrSubnetInUse is returned by DeleteSubnet when addresses are still
+	// assigned from the subnet.
+	ErrSubnetInUse = errors.New("subnet has assigned IP addresses")
+)
+
+// IPAMStore tracks subnet definitions and IP assignments in the store's
+// database, so ncproxy can act as the IPAM source of truth for networks it
+// manages instead of delegating entirely to HNS. Each subnet is a nested
+// bucket keyed by its canonical CIDR, holding assigned address to owner
+// mappings.
+type IPAMStore struct {
+	db *bolt.DB
+}
+
+func NewIPAMStore(db *bolt.DB) *IPAMStore {
+	return &IPAMStore{db: db}
+}
+
+// AddSubnet registers the subnet identified by `subnetCIDR` for allocation.
+// Registering an existing subnet is a no-op.
+func (s *IPAMStore) AddSubnet(ctx context.Context, subnetCIDR string) error {
+	_, ipNet, err := net.ParseCIDR(subnetCIDR)
+	if err != nil {
+		return errors.Wrapf(err, "invalid subnet %v", subnetCIDR)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := createIPAMBucket(tx)
+		if err != nil {
+			return err
+		}
+		_, err = bkt.CreateBucketIfNotExists([]byte(ipNet.String()))
+		return err
+	})
+}
+
+// DeleteSubnet removes the subnet, or returns an error wrapping ErrSubnetInUse
+// if addresses are still assigned from it.
+func (s *IPAMStore) DeleteSubnet(ctx context.Context, subnetCIDR string) error {
+	_, ipNet, err := net.ParseCIDR(subnetCIDR)
+	if err != nil {
+		return errors.Wrapf(err, "invalid subnet %v", subnetCIDR)
+	}
+	key := []byte(ipNet.String())
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := getIPAMBucket(tx)
+		if bkt == nil || bkt.Bucket(key) == nil {
+			return errors.Wrapf(ErrKeyNotFound, "subnet %v", ipNet.String())
+		}
+		if k, _ := bkt.Bucket(key).Cursor().First(); k != nil {
+			return errors.Wrapf(ErrSubnetInUse, "subnet %v", ipNet.String())
+		}
+		return bkt.DeleteBucket(key)
+	})
+}
+
+// ListSubnets returns the canonical CIDRs of the registered subnets.
+func (s *IPAMStore) ListSubnets(ctx context.Context) (results []string, err error) {
+	if err := s.db.View(func(tx *bolt.Tx) error {
+		bkt := getIPAMBucket(tx)
+		if bkt == nil {
+			return nil
+		}
+		return bkt.ForEach(func(k, v []byte) error {
+			if v == nil {
+				results = append(results, string(k))
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// AllocateIP assigns the lowest free usable address in the subnet to `owner`
+// and returns it. The network and (for IPv4) broadcast addresses are never
+// handed out. Returns ErrSubnetExhausted when no usable address is free.
+func (s *IPAMStore) AllocateIP(ctx context.Context, subnetCIDR, owner string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(subnetCIDR)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid subnet %v", subnetCIDR)
+	}
+	var allocated string
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := subnetBucket(tx, ipNet)
+		if err != nil {
+			return err
+		}
+		for candidate := nextIP(ipNet.IP.Mask(ipNet.Mask)); ipNet.Contains(candidate); candidate = nextIP(candidate) {
+			if isBroadcast(candidate, ipNet) {
+				break
+			}
+			if bkt.Get([]byte(candidate.String())) != nil {
+				continue
+			}
+			if err := bkt.Put([]byte(candidate.String()), []byte(owner)); err != nil {
+				return err
+			}
+			allocated = candidate.String()
+			return nil
+		}
+		return errors.Wrapf(ErrSubnetExhausted, "subnet %v", ipNet.String())
+	}); err != nil {
+		return "", err
+	}
+	return allocated, nil
+}
+
+// ReserveStatic assigns the specific address `ip` in the subnet to `owner`,
+// failing if the address is outside the subnet or already assigned.
+func (s *IPAMStore) ReserveStatic(ctx context.Context, subnetCIDR, ip, owner string) error {
+	_, ipNet, err := net.ParseCIDR(subnetCIDR)
+	if err != nil {
+		return errors.Wrapf(err, "invalid subnet %v", subnetCIDR)
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return errors.Errorf("invalid IP address %v", ip)
+	}
+	if !ipNet.Contains(addr) {
+		return errors.Errorf("IP address %v is not in subnet %v", ip, ipNet.String())
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := subnetBucket(tx, ipNet)
+		if err != nil {
+			return err
+		}
+		if bkt.Get([]byte(addr.String())) != nil {
+			return errors.Errorf("IP address %v is already assigned", addr.String())
+		}
+		return bkt.Put([]byte(addr.String()), []byte(owner))
+	})
+}
+
+// ReleaseIP returns `ip` to the subnet, or an error wrapping ErrKeyNotFound
+// if it isn't currently assigned.
+func (s *IPAMStore) ReleaseIP(ctx context.Context, subnetCIDR, ip string) error {
+	_, ipNet, err := net.ParseCIDR(subnetCIDR)
+	if err != nil {
+		return errors.Wrapf(err, "invalid subnet %v", subnetCIDR)
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return errors.Errorf("invalid IP address %v", ip)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := subnetBucket(tx, ipNet)
+		if err != nil {
+			return err
+		}
+		if bkt.Get([]byte(addr.String())) == nil {
+			return errors.Wrapf(ErrKeyNotFound, "IP address %v", addr.String())
+		}
+		return bkt.Delete([]byte(addr.String()))
+	})
+}
+
+func subnetBucket(tx *bolt.Tx, ipNet *net.IPNet) (*bolt.Bucket, error) {
+	bkt := getIPAMBucket(tx)
+	if bkt == nil || bkt.Bucket([]byte(ipNet.String())) == nil {
+		return nil, errors.Wrapf(ErrKeyNotFound, "subnet %v", ipNet.String())
+	}
+	return bkt.Bucket([]byte(ipNet.String())), nil
+}
+
+// nextIP returns the address following `ip`, without mutating its argument.
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+// isBroadcast reports whether `ip` is the IPv4 broadcast address of the
+// subnet. IPv6 subnets have no broadcast address.
+func isBroadcast(ip net.IP, ipNet *net.IPNet) bool {
+	v4 := ip.To4()
+	if v4 == nil {
+		return false
+	}
+	broadcast := make(net.IP, len(v4))
+	network := ipNet.IP.To4()
+	mask := ipNet.Mask
+	if len(mask) != net.IPv4len {
+		mask = mask[len(mask)-net.IPv4len:]
+	}
+	for i := range broadcast {
+		broadcast[i] = network[i] | ^mask[i]
+	}
+	return bytes.Equal(v4, broadcast)
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestIPAMStore(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewIPAMStore(db)
+	subnet := "192.168.0.0/30"
+	if err := store.AddSubnet(ctx, subnet); err != nil {
+		t.Fatal(err)
+	}
+
+	subnets, err := store.ListSubnets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subnets) != 1 || subnets[0] != subnet {
+		t.Fatalf("expected subnet list [%v], got %v", subnet, subnets)
+	}
+
+	// A /30 has two usable addresses; network and broadcast are skipped.
+	expected := []string{"192.168.0.1", "192.168.0.2"}
+	for _, want := range expected {
+		ip, err := store.AllocateIP(ctx, subnet, "fake-endpoint")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ip != want {
+			t.Fatalf("expected IP %v, got %v", want, ip)
+		}
+	}
+	if _, err := store.AllocateIP(ctx, subnet, "fake-endpoint"); !errors.Is(err, ErrSubnetExhausted) {
+		t.Fatalf("expected ErrSubnetExhausted, got %v", err)
+	}
+
+	if err := store.DeleteSubnet(ctx, subnet); !errors.Is(err, ErrSubnetInUse) {
+		t.Fatalf("expected ErrSubnetInUse, got %v", err)
+	}
+
+	if err := store.ReleaseIP(ctx, subnet, "192.168.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	ip, err := store.AllocateIP(ctx, subnet, "fake-endpoint-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip != "192.168.0.1" {
+		t.Fatalf("expected the released IP to be reallocated, got %v", ip)
+	}
+}
+
+func TestIPAMStore_ReserveStatic(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewIPAMStore(db)
+	subnet := "10.0.0.0/24"
+	if err := store.AddSubnet(ctx, subnet); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.ReserveStatic(ctx, subnet, "10.0.0.5", "fake-endpoint"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ReserveStatic(ctx, subnet, "10.0.0.5", "fake-endpoint-2"); err == nil {
+		t.Fatal("expected reserving an assigned address to fail")
+	}
+	if err := store.ReserveStatic(ctx, subnet, "10.1.0.5", "fake-endpoint"); err == nil {
+		t.Fatal("expected reserving an out-of-subnet address to fail")
+	}
+
+	// The reservation is skipped by dynamic allocation.
+	for i := 0; i < 5; i++ {
+		ip, err := store.AllocateIP(ctx, subnet, "fake-endpoint")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ip == "10.0.0.5" {
+			t.Fatal("expected the reserved address to be skipped")
+		}
+	}
+}
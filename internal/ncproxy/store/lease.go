@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// leaseTimeFormat is the serialization format for last-heartbeat timestamps.
+const leaseTimeFormat = time.RFC3339Nano
+
+// RenewComputeAgentLease records the current time as the last heartbeat for
+// the compute agent registered under `containerID`, or returns an error
+// wrapping ErrKeyNotFound if no such registration exists. Leases are
+// optional: entries that are never renewed are ignored by the expiry cleanup.
+func (c *ComputeAgentStore) RenewComputeAgentLease(ctx context.Context, containerID string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		agentBkt := getComputeAgentBucket(tx)
+		if agentBkt == nil || agentBkt.Get([]byte(containerID)) == nil {
+			return errors.Wrapf(ErrKeyNotFound, "compute agent %v", containerID)
+		}
+		bkt, err := createComputeAgentLeaseBucket(tx)
+		if err != nil {
+			return err
+		}
+		return bkt.Put([]byte(containerID), []byte(time.Now().UTC().Format(leaseTimeFormat)))
+	})
+}
+
+// CleanupExpiredComputeAgents removes compute agent registrations whose lease
+// has not been renewed within `ttl`, returning the container IDs removed.
+// Registrations without a lease are left alone, so agents that predate lease
+// support (or opt out of heartbeating) are never reaped.
+func (c *ComputeAgentStore) CleanupExpiredComputeAgents(ctx context.Context, ttl time.Duration) ([]string, error) {
+	var expired []string
+	if err := c.db.Update(func(tx *bolt.Tx) error {
+		expired = nil
+		leaseBkt := getComputeAgentLeaseBucket(tx)
+		if leaseBkt == nil {
+			return nil
+		}
+		deadline := time.Now().UTC().Add(-ttl)
+		if err := leaseBkt.ForEach(func(k, v []byte) error {
+			heartbeat, err := time.Parse(leaseTimeFormat, string(v))
+			if err != nil {
+				return errors.Wrapf(err, "malformed lease timestamp %v for compute agent %v", string(v), string(k))
+			}
+			if heartbeat.Before(deadline) {
+				expired = append(expired, string(k))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		agentBkt := getComputeAgentBucket(tx)
+		for _, containerID := range expired {
+			if agentBkt != nil {
+				if err := agentBkt.Delete([]byte(containerID)); err != nil {
+					return err
+				}
+			}
+			if err := leaseBkt.Delete([]byte(containerID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, containerID := range expired {
+		c.notify(ComputeAgentEvent{Type: ComputeAgentDeleted, ContainerID: containerID})
+	}
+	return expired, nil
+}
+
+// RunLeaseCleanup removes expired compute agent registrations every
+// `interval` until `ctx` is cancelled, logging (rather than returning)
+// cleanup failures. It blocks, so callers typically run it in a goroutine.
+func (c *ComputeAgentStore) RunLeaseCleanup(ctx context.Context, ttl, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		removed, err := c.CleanupExpiredComputeAgents(ctx, ttl)
+		if err != nil {
+			log.G(ctx).WithError(err).Error("compute agent lease cleanup failed")
+			continue
+		}
+		if len(removed) != 0 {
+			log.G(ctx).WithField("containerIDs", removed).Info("removed expired compute agent registrations")
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestComputeAgentStore_LeaseCleanup(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewComputeAgentStore(db)
+	leased := "fake-container-id"
+	unleased := "fake-container-id-2"
+	if err := store.UpdateComputeAgent(ctx, leased, "123412341234"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpdateComputeAgent(ctx, unleased, "234523452345"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RenewComputeAgentLease(ctx, leased); err != nil {
+		t.Fatal(err)
+	}
+
+	// A freshly renewed lease isn't expired.
+	removed, err := store.CleanupExpiredComputeAgents(ctx, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 0 {
+		t.Fatalf("expected no expired registrations, got %v", removed)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	removed, err = store.CleanupExpiredComputeAgents(ctx, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0] != leased {
+		t.Fatalf("expected %v to be removed, got %v", leased, removed)
+	}
+	if _, err := store.GetComputeAgent(ctx, leased); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected the expired registration to be gone, got %v", err)
+	}
+
+	// Registrations without a lease are never reaped.
+	if _, err := store.GetComputeAgent(ctx, unleased); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestComputeAgentStore_RenewLeaseMissingAgent(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewComputeAgentStore(db)
+	if err := store.RenewComputeAgentLease(ctx, "fake-container-id"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrMACPoolExhausted is returned by AllocateMAC when every address in the
+// pool's range is assigned.
+var ErrMACPoolExhausted = errors.New("no free MAC addresses in pool")
+
+// MACPool hands out MAC addresses from a configured range, persisting
+// assignments in the store's database so endpoints get deterministic,
+// conflict-free MACs across service restarts.
+type MACPool struct {
+	db    *bolt.DB
+	start uint64
+	end   uint64
+}
+
+// NewMACPool returns a pool allocating from `startMAC` through `endMAC`
+// inclusive, e.g. an OUI-scoped range like 00-15-5D-00-00-00 to
+// 00-15-5D-00-FF-FF. Both bounds must be 48-bit addresses.
+func NewMACPool(db *bolt.DB, startMAC, endMAC string) (*MACPool, error) {
+	start, err := parseMAC(startMAC)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseMAC(endMAC)
+	if err != nil {
+		return nil, err
+	}
+	if start > end {
+		return nil, errors.Errorf("MAC pool start %s is after end %s", startMAC, endMAC)
+	}
+	return &MACPool{db: db, start: start, end: end}, nil
+}
+
+// AllocateMAC assigns the lowest free MAC address in the pool's range to
+// `owner` and returns it in canonical dashed form. Returns
+// ErrMACPoolExhausted when the range is fully assigned.
+func (p *MACPool) AllocateMAC(ctx context.Context, owner string) (string, error) {
+	var allocated string
+	if err := p.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := createMACPoolBucket(tx)
+		if err != nil {
+			return err
+		}
+		for candidate := p.start; candidate <= p.end; candidate++ {
+			mac := formatMAC(candidate)
+			if bkt.Get([]byte(mac)) != nil {
+				continue
+			}
+			if err := bkt.Put([]byte(mac), []byte(owner)); err != nil {
+				return err
+			}
+			allocated = mac
+			return nil
+		}
+		return errors.Wrapf(ErrMACPoolExhausted, "range %s-%s", formatMAC(p.start), formatMAC(p.end))
+	}); err != nil {
+		return "", err
+	}
+	return allocated, nil
+}
+
+// ReleaseMAC returns `mac` to the pool, or an error wrapping ErrKeyNotFound
+// if it isn't currently assigned.
+func (p *MACPool) ReleaseMAC(ctx context.Context, mac string) error {
+	value, err := parseMAC(mac)
+	if err != nil {
+		return err
+	}
+	key := formatMAC(value)
+	return p.db.Update(func(tx *bolt.Tx) error {
+		bkt := getMACPoolBucket(tx)
+		if bkt == nil || bkt.Get([]byte(key)) == nil {
+			return errors.Wrapf(ErrKeyNotFound, "MAC address %v", key)
+		}
+		return bkt.Delete([]byte(key))
+	})
+}
+
+func parseMAC(mac string) (uint64, error) {
+	hwAddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid MAC address %v", mac)
+	}
+	if len(hwAddr) != 6 {
+		return 0, errors.Errorf("MAC address %v is not a 48-bit address", mac)
+	}
+	buf := make([]byte, 8)
+	copy(buf[2:], hwAddr)
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+func formatMAC(value uint64) string {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, value)
+	parts := make([]string, 6)
+	for i, b := range buf[2:] {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, "-")
+}
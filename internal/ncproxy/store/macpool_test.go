@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestMACPool(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	pool, err := NewMACPool(db, "00-15-5D-00-00-00", "00-15-5D-00-00-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"00-15-5D-00-00-00", "00-15-5D-00-00-01", "00-15-5D-00-00-02"}
+	for _, want := range expected {
+		mac, err := pool.AllocateMAC(ctx, "fake-endpoint")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mac != want {
+			t.Fatalf("expected MAC %v, got %v", want, mac)
+		}
+	}
+
+	if _, err := pool.AllocateMAC(ctx, "fake-endpoint"); !errors.Is(err, ErrMACPoolExhausted) {
+		t.Fatalf("expected ErrMACPoolExhausted, got %v", err)
+	}
+
+	if err := pool.ReleaseMAC(ctx, "00-15-5D-00-00-01"); err != nil {
+		t.Fatal(err)
+	}
+	// The lowest free address is handed out again.
+	mac, err := pool.AllocateMAC(ctx, "fake-endpoint-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mac != "00-15-5D-00-00-01" {
+		t.Fatalf("expected the released MAC to be reallocated, got %v", mac)
+	}
+
+	if err := pool.ReleaseMAC(ctx, "00-15-5D-00-00-FF"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestNewMACPool_InvalidRange(t *testing.T) {
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := NewMACPool(db, "00-15-5D-00-00-02", "00-15-5D-00-00-00"); err == nil {
+		t.Fatal("expected an error for a reversed range")
+	}
+	if _, err := NewMACPool(db, "not-a-mac", "00-15-5D-00-00-00"); err == nil {
+		t.Fatal("expected an error for a malformed address")
+	}
+}
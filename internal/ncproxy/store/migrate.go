@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// currentSchemaVersion is the schema generation this code reads and writes.
+// It is recorded in the metadata bucket so that upgraded binaries can migrate
+// records that changed shape instead of silently failing to unmarshal them.
+const currentSchemaVersion = 1
+
+var (
+	bucketKeyMetadata = []byte("metadata")
+	keySchemaVersion  = []byte("schemaVersion")
+)
+
+// ErrSchemaTooNew is returned by Migrate when the database was written by a
+// newer binary than the one opening it.
+var ErrSchemaTooNew = errors.New("database schema is newer than this binary supports")
+
+// migration upgrades the database contents from one schema version to the
+// next within the supplied transaction.
+type migration func(tx *bolt.Tx) error
+
+// migrations[i] upgrades schema version i+1 to version i+2. Append a new
+// entry (and bump currentSchemaVersion) whenever the Network/Endpoint record
+// shape changes incompatibly.
+var migrations = []migration{}
+
+// Migrate brings the database's schema up to currentSchemaVersion, running
+// any pending migrations in order within a single update, and records the
+// resulting version in the metadata bucket. Databases without a recorded
+// version are treated as version 1, the shape in use before versioning was
+// introduced. A database written by a newer binary fails with
+// ErrSchemaTooNew rather than being misread.
+func Migrate(ctx context.Context, db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		version, err := readSchemaVersion(tx)
+		if err != nil {
+			return err
+		}
+		if version > currentSchemaVersion {
+			return errors.Wrapf(ErrSchemaTooNew, "database schema version %d, supported version %d", version, currentSchemaVersion)
+		}
+		for v := version; v < currentSchemaVersion; v++ {
+			if err := migrations[v-1](tx); err != nil {
+				return errors.Wrapf(err, "migrating database schema from version %d to %d", v, v+1)
+			}
+		}
+		return writeSchemaVersion(tx, currentSchemaVersion)
+	})
+}
+
+func readSchemaVersion(tx *bolt.Tx) (int, error) {
+	bkt := getBucket(tx, bucketKeyVersion, bucketKeyMetadata)
+	if bkt == nil {
+		return 1, nil
+	}
+	data := bkt.Get(keySchemaVersion)
+	if data == nil {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(string(data))
+	if err != nil {
+		return 0, errors.Wrapf(err, "malformed schema version %v", string(data))
+	}
+	return version, nil
+}
+
+func writeSchemaVersion(tx *bolt.Tx, version int) error {
+	bkt, err := createBucketIfNotExists(tx, bucketKeyVersion, bucketKeyMetadata)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(keySchemaVersion, []byte(strconv.Itoa(version)))
+}
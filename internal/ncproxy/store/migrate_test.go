@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := Migrate(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.View(func(tx *bolt.Tx) error {
+		bkt := getBucket(tx, bucketKeyVersion, bucketKeyMetadata)
+		if bkt == nil {
+			return errors.New("expected the metadata bucket to exist")
+		}
+		data := bkt.Get(keySchemaVersion)
+		if string(data) != strconv.Itoa(currentSchemaVersion) {
+			return errors.New("unexpected schema version " + string(data))
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Running again against an up-to-date database is a no-op.
+	if err := Migrate(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrate_SchemaTooNew(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		return writeSchemaVersion(tx, currentSchemaVersion+1)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Migrate(ctx, db); !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"sync"
 
 	ncproxynetworking "github.com/Microsoft/hcsshim/internal/ncproxy/networking"
 	"github.com/pkg/errors"
@@ -12,6 +13,9 @@ import (
 var (
 	ErrBucketNotFound = errors.New("bucket not found")
 	ErrKeyNotFound    = errors.New("key does not exist")
+	// ErrNetworkInUse is returned by DeleteNetwork when endpoints still
+	// reference the network and force was not set.
+	ErrNetworkInUse = errors.New("network is in use")
 )
 
 type NetworkingStore struct {
@@ -66,12 +70,37 @@ func (n *NetworkingStore) CreateNetwork(ctx context.Context, network *ncproxynet
 	return nil
 }
 
-func (n *NetworkingStore) DeleteNetwork(ctx context.Context, networkName string) error {
+// DeleteNetwork removes the network record for `networkName`. Unless `force`
+// is set, the endpoint bucket is checked within the same transaction and the
+// delete is refused with ErrNetworkInUse (listing the referencing endpoints)
+// if any endpoint still references the network, so dangling endpoint
+// references are prevented at the source.
+func (n *NetworkingStore) DeleteNetwork(ctx context.Context, networkName string, force bool) error {
 	if err := n.db.Update(func(tx *bolt.Tx) error {
 		bkt := getNetworkBucket(tx)
 		if bkt == nil {
 			return errors.Wrapf(ErrBucketNotFound, "bucket %v", bucketKeyNetwork)
 		}
+		if !force {
+			var referencing []string
+			if endptBkt := getEndpointBucket(tx); endptBkt != nil {
+				if err := endptBkt.ForEach(func(k, v []byte) error {
+					endpt := &ncproxynetworking.Endpoint{}
+					if err := json.Unmarshal(v, endpt); err != nil {
+						return errors.Wrapf(err, "data is %v", string(v))
+					}
+					if endpt.Settings != nil && endpt.Settings.NetworkName == networkName {
+						referencing = append(referencing, endpt.EndpointName)
+					}
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+			if len(referencing) != 0 {
+				return errors.Wrapf(ErrNetworkInUse, "network %v is referenced by endpoints %v", networkName, referencing)
+			}
+		}
 		return bkt.Delete([]byte(networkName))
 	}); err != nil {
 		return err
@@ -101,6 +130,179 @@ func (n *NetworkingStore) ListNetworks(ctx context.Context) (results []*ncproxyn
 	return results, nil
 }
 
+// CreateNetworkWithEndpoints writes the network record and all of its
+// endpoint records in a single bolt update, so a crash can't leave a network
+// without its endpoints (or the reverse) behind.
+func (n *NetworkingStore) CreateNetworkWithEndpoints(ctx context.Context, network *ncproxynetworking.Network, endpoints ...*ncproxynetworking.Endpoint) error {
+	return n.db.Update(func(tx *bolt.Tx) error {
+		netBkt, err := createNetworkBucket(tx)
+		if err != nil {
+			return err
+		}
+		internalData, err := json.Marshal(network)
+		if err != nil {
+			return err
+		}
+		if err := netBkt.Put([]byte(network.NetworkName), internalData); err != nil {
+			return err
+		}
+		endptBkt, err := createEndpointBucket(tx)
+		if err != nil {
+			return err
+		}
+		for _, endpt := range endpoints {
+			jsonEndptData, err := json.Marshal(endpt)
+			if err != nil {
+				return err
+			}
+			if err := endptBkt.Put([]byte(endpt.EndpointName), jsonEndptData); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteNetworkCascade removes the network record for `networkName` together
+// with every endpoint record referencing it, in a single bolt update.
+func (n *NetworkingStore) DeleteNetworkCascade(ctx context.Context, networkName string) error {
+	return n.db.Update(func(tx *bolt.Tx) error {
+		bkt := getNetworkBucket(tx)
+		if bkt == nil {
+			return errors.Wrapf(ErrBucketNotFound, "bucket %v", bucketKeyNetwork)
+		}
+		if endptBkt := getEndpointBucket(tx); endptBkt != nil {
+			var referencing [][]byte
+			if err := endptBkt.ForEach(func(k, v []byte) error {
+				endpt := &ncproxynetworking.Endpoint{}
+				if err := json.Unmarshal(v, endpt); err != nil {
+					return errors.Wrapf(err, "data is %v", string(v))
+				}
+				if endpt.Settings != nil && endpt.Settings.NetworkName == networkName {
+					referencing = append(referencing, append([]byte(nil), k...))
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			for _, k := range referencing {
+				if err := endptBkt.Delete(k); err != nil {
+					return err
+				}
+			}
+		}
+		return bkt.Delete([]byte(networkName))
+	})
+}
+
+// ListNetworksPaged returns up to `limit` network records with names strictly
+// after `cursor` in key order, plus the cursor to pass for the next page
+// (empty once the listing is exhausted). Pass an empty cursor to start from
+// the beginning. Unlike ListNetworks, each page holds the bolt read
+// transaction only for its own scan.
+func (n *NetworkingStore) ListNetworksPaged(ctx context.Context, cursor string, limit int) (results []*ncproxynetworking.Network, nextCursor string, err error) {
+	if limit <= 0 {
+		return nil, "", errors.New("limit must be positive")
+	}
+	if err := n.db.View(func(tx *bolt.Tx) error {
+		bkt := getNetworkBucket(tx)
+		if bkt == nil {
+			return errors.Wrapf(ErrBucketNotFound, "network bucket %v", bucketKeyNetwork)
+		}
+		k, v, c := seekPastCursor(bkt, cursor)
+		for ; k != nil; k, v = c.Next() {
+			internalData := &ncproxynetworking.Network{}
+			if err := json.Unmarshal(v, internalData); err != nil {
+				return errors.Wrapf(err, "data is %v", string(v))
+			}
+			results = append(results, internalData)
+			if len(results) == limit {
+				if next, _ := c.Next(); next != nil {
+					nextCursor = string(k)
+				}
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, "", err
+	}
+	return results, nextCursor, nil
+}
+
+// ListEndpointsPaged returns up to `limit` endpoint records with names
+// strictly after `cursor` in key order, plus the cursor for the next page
+// (empty once the listing is exhausted).
+func (n *NetworkingStore) ListEndpointsPaged(ctx context.Context, cursor string, limit int) (results []*ncproxynetworking.Endpoint, nextCursor string, err error) {
+	if limit <= 0 {
+		return nil, "", errors.New("limit must be positive")
+	}
+	if err := n.db.View(func(tx *bolt.Tx) error {
+		bkt := getEndpointBucket(tx)
+		if bkt == nil {
+			return errors.Wrapf(ErrBucketNotFound, "endpoint bucket %v", bucketKeyEndpoint)
+		}
+		k, v, c := seekPastCursor(bkt, cursor)
+		for ; k != nil; k, v = c.Next() {
+			endptInternal := &ncproxynetworking.Endpoint{}
+			if err := json.Unmarshal(v, endptInternal); err != nil {
+				return errors.Wrapf(err, "data is %v", string(v))
+			}
+			results = append(results, endptInternal)
+			if len(results) == limit {
+				if next, _ := c.Next(); next != nil {
+					nextCursor = string(k)
+				}
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, "", err
+	}
+	return results, nextCursor, nil
+}
+
+// ListEndpointsByNetwork returns the endpoint records whose settings reference
+// `networkName`. Endpoints are keyed by endpoint name, so this is a filtered
+// scan of the endpoint bucket rather than a prefix seek.
+func (n *NetworkingStore) ListEndpointsByNetwork(ctx context.Context, networkName string) (results []*ncproxynetworking.Endpoint, err error) {
+	if err := n.db.View(func(tx *bolt.Tx) error {
+		bkt := getEndpointBucket(tx)
+		if bkt == nil {
+			return errors.Wrapf(ErrBucketNotFound, "endpoint bucket %v", bucketKeyEndpoint)
+		}
+		return bkt.ForEach(func(k, v []byte) error {
+			endptInternal := &ncproxynetworking.Endpoint{}
+			if err := json.Unmarshal(v, endptInternal); err != nil {
+				return errors.Wrapf(err, "data is %v", string(v))
+			}
+			if endptInternal.Settings != nil && endptInternal.Settings.NetworkName == networkName {
+				results = append(results, endptInternal)
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// seekPastCursor positions a cursor on the first key strictly after `cursor`,
+// or on the first key in the bucket when `cursor` is empty.
+func seekPastCursor(bkt *bolt.Bucket, cursor string) ([]byte, []byte, *bolt.Cursor) {
+	c := bkt.Cursor()
+	if cursor == "" {
+		k, v := c.First()
+		return k, v, c
+	}
+	k, v := c.Seek([]byte(cursor))
+	if k != nil && string(k) == cursor {
+		k, v = c.Next()
+	}
+	return k, v, c
+}
+
 func (n *NetworkingStore) GetEndpointByName(ctx context.Context, endpointName string) (*ncproxynetworking.Endpoint, error) {
 	endpt := &ncproxynetworking.Endpoint{}
 	if err := n.db.View(func(tx *bolt.Tx) error {
@@ -148,6 +350,50 @@ func (n *NetworkingStore) updateEndpoint(ctx context.Context, endpt *ncproxynetw
 	return nil
 }
 
+// SetEndpointMetadata stores `key`/`value` in the metadata of the endpoint
+// record for `endpointName`. The read-modify-write happens in a single
+// transaction so metadata updates stay atomic within the endpoint bucket.
+func (n *NetworkingStore) SetEndpointMetadata(ctx context.Context, endpointName, key, value string) error {
+	return n.db.Update(func(tx *bolt.Tx) error {
+		bkt := getEndpointBucket(tx)
+		if bkt == nil {
+			return errors.Wrapf(ErrBucketNotFound, "endpoint bucket %v", bucketKeyEndpoint)
+		}
+		jsonData := bkt.Get([]byte(endpointName))
+		if jsonData == nil {
+			return errors.Wrapf(ErrKeyNotFound, "endpoint %v", endpointName)
+		}
+		endpt := &ncproxynetworking.Endpoint{}
+		if err := json.Unmarshal(jsonData, endpt); err != nil {
+			return err
+		}
+		if endpt.Metadata == nil {
+			endpt.Metadata = make(map[string]string)
+		}
+		endpt.Metadata[key] = value
+		jsonEndptData, err := json.Marshal(endpt)
+		if err != nil {
+			return err
+		}
+		return bkt.Put([]byte(endpt.EndpointName), jsonEndptData)
+	})
+}
+
+// GetEndpointMetadata returns the metadata value stored under `key` for the
+// endpoint record for `endpointName`, or ErrKeyNotFound if either the
+// endpoint or the key doesn't exist.
+func (n *NetworkingStore) GetEndpointMetadata(ctx context.Context, endpointName, key string) (string, error) {
+	endpt, err := n.GetEndpointByName(ctx, endpointName)
+	if err != nil {
+		return "", err
+	}
+	value, ok := endpt.Metadata[key]
+	if !ok {
+		return "", errors.Wrapf(ErrKeyNotFound, "metadata key %v on endpoint %v", key, endpointName)
+	}
+	return value, nil
+}
+
 func (n *NetworkingStore) DeleteEndpoint(ctx context.Context, endpointName string) error {
 	if err := n.db.Update(func(tx *bolt.Tx) error {
 		bkt := getEndpointBucket(tx)
@@ -187,6 +433,9 @@ func (n *NetworkingStore) ListEndpoints(ctx context.Context) (results []*ncproxy
 // to compute agent server address
 type ComputeAgentStore struct {
 	db *bolt.DB
+
+	watchMu  sync.Mutex
+	watchers map[*computeAgentWatcher]struct{}
 }
 
 func NewComputeAgentStore(db *bolt.DB) *ComputeAgentStore {
@@ -242,29 +491,46 @@ func (c *ComputeAgentStore) GetComputeAgents(ctx context.Context) (map[string]st
 // UpdateComputeAgent updates or adds an entry (if none already exists) to the database
 // `address` corresponds to the address of the compute agent server for the `containerID`
 func (c *ComputeAgentStore) UpdateComputeAgent(ctx context.Context, containerID string, address string) error {
+	var existed bool
 	if err := c.db.Update(func(tx *bolt.Tx) error {
 		bkt, err := createComputeAgentBucket(tx)
 		if err != nil {
 			return err
 		}
+		existed = bkt.Get([]byte(containerID)) != nil
 		return bkt.Put([]byte(containerID), []byte(address))
 	}); err != nil {
 		return err
 	}
+	eventType := ComputeAgentAdded
+	if existed {
+		eventType = ComputeAgentUpdated
+	}
+	c.notify(ComputeAgentEvent{Type: eventType, ContainerID: containerID, Address: address})
 	return nil
 }
 
 // DeleteComputeAgent deletes an entry in the database or returns an error if none exists
 // `containerID` corresponds to the target key that the entry should be deleted for
 func (c *ComputeAgentStore) DeleteComputeAgent(ctx context.Context, containerID string) error {
+	var existed bool
 	if err := c.db.Update(func(tx *bolt.Tx) error {
 		bkt := getComputeAgentBucket(tx)
 		if bkt == nil {
 			return errors.Wrapf(ErrBucketNotFound, "bucket %v", bucketKeyComputeAgent)
 		}
+		existed = bkt.Get([]byte(containerID)) != nil
+		if leaseBkt := getComputeAgentLeaseBucket(tx); leaseBkt != nil {
+			if err := leaseBkt.Delete([]byte(containerID)); err != nil {
+				return err
+			}
+		}
 		return bkt.Delete([]byte(containerID))
 	}); err != nil {
 		return err
 	}
+	if existed {
+		c.notify(ComputeAgentEvent{Type: ComputeAgentDeleted, ContainerID: containerID})
+	}
 	return nil
 }
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 	"path/filepath"
 	"testing"
 
@@ -127,6 +128,63 @@ func TestEndpointStore(t *testing.T) {
 	}
 }
 
+func TestEndpointStore_Metadata(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewNetworkingStore(db)
+	endpointName := "test-endpoint-name"
+
+	endpoint := &ncproxynetworking.Endpoint{
+		EndpointName: endpointName,
+	}
+
+	if err := store.CreatEndpoint(ctx, endpoint); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetEndpointMetadata(ctx, endpointName, "pod-name", "test-pod"); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := store.GetEndpointMetadata(ctx, endpointName, "pod-name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != "test-pod" {
+		t.Fatalf("metadata value is not equal, expected %v but got %v", "test-pod", actual)
+	}
+
+	if err := store.SetEndpointMetadata(ctx, endpointName, "pod-name", "test-pod-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err = store.GetEndpointMetadata(ctx, endpointName, "pod-name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != "test-pod-2" {
+		t.Fatalf("metadata value is not equal, expected %v but got %v", "test-pod-2", actual)
+	}
+
+	value, err := store.GetEndpointMetadata(ctx, endpointName, "missing-key")
+	if err == nil {
+		t.Fatalf("expected an error, instead found value %s", value)
+	}
+
+	if err := store.SetEndpointMetadata(ctx, "missing-endpoint", "pod-name", "test-pod"); err == nil {
+		t.Fatal("expected an error setting metadata on a missing endpoint")
+	}
+}
+
 func TestEndpointStore_GetAll(t *testing.T) {
 	ctx := context.Background()
 	tempDir := t.TempDir()
@@ -202,7 +260,7 @@ func TestNetworkStore(t *testing.T) {
 		t.Fatalf("network name is not equal, expected %v but got %v", networkName, actual.NetworkName)
 	}
 
-	if err := store.DeleteNetwork(ctx, networkName); err != nil {
+	if err := store.DeleteNetwork(ctx, networkName, false); err != nil {
 		t.Fatal(err)
 	}
 
@@ -212,6 +270,64 @@ func TestNetworkStore(t *testing.T) {
 	}
 }
 
+func TestNetworkStore_DeleteInUse(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewNetworkingStore(db)
+	networkName := "test-network-name"
+	endpointName := "test-endpoint-name"
+
+	if err := store.CreateNetwork(ctx, &ncproxynetworking.Network{
+		NetworkName: networkName,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.CreatEndpoint(ctx, &ncproxynetworking.Endpoint{
+		EndpointName: endpointName,
+		Settings: &ncproxynetworking.EndpointSettings{
+			Name:        endpointName,
+			NetworkName: networkName,
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.DeleteNetwork(ctx, networkName, false)
+	if !errors.Is(err, ErrNetworkInUse) {
+		t.Fatalf("expected ErrNetworkInUse deleting a referenced network, got %v", err)
+	}
+
+	if _, err := store.GetNetworkByName(ctx, networkName); err != nil {
+		t.Fatalf("expected network to still exist after refused delete, got %v", err)
+	}
+
+	if err := store.DeleteNetwork(ctx, networkName, true); err != nil {
+		t.Fatalf("expected forced delete to succeed, got %v", err)
+	}
+
+	if err := store.DeleteEndpoint(ctx, endpointName); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.CreateNetwork(ctx, &ncproxynetworking.Network{
+		NetworkName: networkName,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DeleteNetwork(ctx, networkName, false); err != nil {
+		t.Fatalf("expected unreferenced delete to succeed, got %v", err)
+	}
+}
+
 func TestNetworkStore_GetAll(t *testing.T) {
 	ctx := context.Background()
 	tempDir := t.TempDir()
@@ -256,3 +372,144 @@ func TestNetworkStore_GetAll(t *testing.T) {
 		}
 	}
 }
+
+func TestNetworkStore_ListNetworksPaged(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewNetworkingStore(db)
+	networkNames := []string{"network-name-1", "network-name-2", "network-name-3"}
+	for _, name := range networkNames {
+		if err := store.CreateNetwork(ctx, &ncproxynetworking.Network{NetworkName: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, cursor, err := store.ListNetworksPaged(ctx, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 {
+		t.Fatalf("expected 2 networks in the first page, got %d", len(page))
+	}
+	if cursor != networkNames[1] {
+		t.Fatalf("expected cursor %v, got %v", networkNames[1], cursor)
+	}
+
+	page, cursor, err = store.ListNetworksPaged(ctx, cursor, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 {
+		t.Fatalf("expected 1 network in the second page, got %d", len(page))
+	}
+	if page[0].NetworkName != networkNames[2] {
+		t.Fatalf("expected network %v in the second page, got %v", networkNames[2], page[0].NetworkName)
+	}
+	if cursor != "" {
+		t.Fatalf("expected an empty cursor at the end of the listing, got %v", cursor)
+	}
+
+	if _, _, err := store.ListNetworksPaged(ctx, "", 0); err == nil {
+		t.Fatal("expected an error for a non-positive limit")
+	}
+}
+
+func TestEndpointStore_ListEndpointsByNetwork(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewNetworkingStore(db)
+	endpoints := []*ncproxynetworking.Endpoint{
+		{
+			EndpointName: "endpoint-name-1",
+			Settings:     &ncproxynetworking.EndpointSettings{NetworkName: "network-name-1"},
+		},
+		{
+			EndpointName: "endpoint-name-2",
+			Settings:     &ncproxynetworking.EndpointSettings{NetworkName: "network-name-2"},
+		},
+		{
+			EndpointName: "endpoint-name-3",
+			Settings:     &ncproxynetworking.EndpointSettings{NetworkName: "network-name-1"},
+		},
+	}
+	for _, endpt := range endpoints {
+		if err := store.CreatEndpoint(ctx, endpt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	actual, err := store.ListEndpointsByNetwork(ctx, "network-name-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 2 {
+		t.Fatalf("expected 2 endpoints on the network, got %d", len(actual))
+	}
+	for _, endpt := range actual {
+		if endpt.Settings.NetworkName != "network-name-1" {
+			t.Fatalf("unexpected endpoint %v on network %v", endpt.EndpointName, endpt.Settings.NetworkName)
+		}
+	}
+}
+
+func TestNetworkStore_DeleteNetworkCascade(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewNetworkingStore(db)
+	network := &ncproxynetworking.Network{NetworkName: "network-name-1"}
+	endpoints := []*ncproxynetworking.Endpoint{
+		{
+			EndpointName: "endpoint-name-1",
+			Settings:     &ncproxynetworking.EndpointSettings{NetworkName: "network-name-1"},
+		},
+		{
+			EndpointName: "endpoint-name-2",
+			Settings:     &ncproxynetworking.EndpointSettings{NetworkName: "network-name-2"},
+		},
+	}
+	if err := store.CreateNetworkWithEndpoints(ctx, network, endpoints...); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.GetNetworkByName(ctx, "network-name-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetEndpointByName(ctx, "endpoint-name-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DeleteNetworkCascade(ctx, "network-name-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.GetNetworkByName(ctx, "network-name-1"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected the network to be deleted, got %v", err)
+	}
+	if _, err := store.GetEndpointByName(ctx, "endpoint-name-1"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected the referencing endpoint to be deleted, got %v", err)
+	}
+	if _, err := store.GetEndpointByName(ctx, "endpoint-name-2"); err != nil {
+		t.Fatalf("expected the unrelated endpoint to survive, got %v", err)
+	}
+}
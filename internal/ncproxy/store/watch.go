@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+)
+
+// ComputeAgentEventType identifies the kind of change carried by a
+// ComputeAgentEvent.
+type ComputeAgentEventType string
+
+const (
+	ComputeAgentAdded   ComputeAgentEventType = "Added"
+	ComputeAgentUpdated ComputeAgentEventType = "Updated"
+	ComputeAgentDeleted ComputeAgentEventType = "Deleted"
+)
+
+// ComputeAgentEvent describes a change to a containerID to compute agent
+// address mapping in the store.
+type ComputeAgentEvent struct {
+	Type        ComputeAgentEventType
+	ContainerID string
+	// Address is the compute agent address after the change; empty for
+	// deletes.
+	Address string
+}
+
+// computeAgentEventBuffer is the per-watcher channel depth. Events beyond it
+// are dropped rather than blocking store writes; watchers that fall behind
+// can resynchronize with GetComputeAgents.
+const computeAgentEventBuffer = 32
+
+type computeAgentWatcher struct {
+	ch chan ComputeAgentEvent
+}
+
+// Watch returns a channel of add/update/delete events for the containerID to
+// compute agent address mappings in the store, so consumers such as the
+// ncproxy reconnect loop can react to new compute agents without polling.
+// Only changes made through this ComputeAgentStore instance are observed. The
+// channel is closed when `ctx` is cancelled.
+func (c *ComputeAgentStore) Watch(ctx context.Context) <-chan ComputeAgentEvent {
+	w := &computeAgentWatcher{ch: make(chan ComputeAgentEvent, computeAgentEventBuffer)}
+	c.watchMu.Lock()
+	if c.watchers == nil {
+		c.watchers = make(map[*computeAgentWatcher]struct{})
+	}
+	c.watchers[w] = struct{}{}
+	c.watchMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		c.watchMu.Lock()
+		defer c.watchMu.Unlock()
+		if _, ok := c.watchers[w]; ok {
+			delete(c.watchers, w)
+			close(w.ch)
+		}
+	}()
+	return w.ch
+}
+
+// notify fans an event out to the store's watchers. It must not block: sends
+// to watchers that are not keeping up are dropped.
+func (c *ComputeAgentStore) notify(event ComputeAgentEvent) {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+	for w := range c.watchers {
+		select {
+		case w.ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestComputeAgentStore_Watch(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	db, err := bolt.Open(filepath.Join(tempDir, "networkproxy.db.test"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewComputeAgentStore(db)
+	watchCtx, cancel := context.WithCancel(ctx)
+	events := store.Watch(watchCtx)
+
+	containerID := "fake-container-id"
+	if err := store.UpdateComputeAgent(ctx, containerID, "123412341234"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpdateComputeAgent(ctx, containerID, "234523452345"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteComputeAgent(ctx, containerID); err != nil {
+		t.Fatal(err)
+	}
+	// Deleting a missing entry produces no event.
+	if err := store.DeleteComputeAgent(ctx, containerID); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ComputeAgentEvent{
+		{Type: ComputeAgentAdded, ContainerID: containerID, Address: "123412341234"},
+		{Type: ComputeAgentUpdated, ContainerID: containerID, Address: "234523452345"},
+		{Type: ComputeAgentDeleted, ContainerID: containerID},
+	}
+	for _, want := range expected {
+		select {
+		case got := <-events:
+			if got != want {
+				t.Fatalf("expected event %+v, got %+v", want, got)
+			}
+		default:
+			t.Fatalf("expected event %+v to be delivered", want)
+		}
+	}
+	select {
+	case got := <-events:
+		t.Fatalf("unexpected event %+v", got)
+	default:
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Fatal("expected the watch channel to be closed after cancellation")
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 
+	"golang.org/x/sys/windows/registry"
+
 	"github.com/Microsoft/hcsshim/internal/hcs"
 	hcsschema "github.com/Microsoft/hcsshim/internal/hcs/schema2"
 )
@@ -32,3 +34,30 @@ func HostProcessorInfo(ctx context.Context) (*hcsschema.ProcessorTopology, error
 	}
 	return processorTopology, nil
 }
+
+// HostNumaProcessorCounts returns the number of logical processors on each of
+// the host's NUMA nodes, keyed by node number. Useful together with
+// HostProcessorInfo when deciding how to place a large UVM across nodes.
+func HostNumaProcessorCounts(topology *hcsschema.ProcessorTopology) map[uint8]uint32 {
+	counts := make(map[uint8]uint32)
+	for _, lp := range topology.LogicalProcessors {
+		counts[lp.NodeNumber]++
+	}
+	return counts
+}
+
+// HostProcessorFrequencyMHz returns the frequency of the host's first logical
+// processor, as recorded by the firmware in the registry.
+func HostProcessorFrequencyMHz() (uint32, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`HARDWARE\DESCRIPTION\System\CentralProcessor\0`, registry.QUERY_VALUE)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open processor description key: %w", err)
+	}
+	defer k.Close()
+	mhz, _, err := k.GetIntegerValue("~MHz")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read processor frequency: %w", err)
+	}
+	return uint32(mhz), nil
+}
@@ -23,6 +23,13 @@ const (
 	// for operations on the SCSI device itself.
 	// Currently it only supports Remove, to cleanly remove a SCSI device.
 	ResourceTypeSCSIDevice guestrequest.ResourceType = "SCSIDevice"
+	// ResourceTypeSCSITrim is the modify resource type for flushing and
+	// trimming a SCSI device's free blocks ahead of detach.
+	ResourceTypeSCSITrim guestrequest.ResourceType = "SCSITrim"
+	// ResourceTypeSCSIFilesystemResize is the modify resource type for growing
+	// the filesystem on a SCSI device online, after its backing virtual disk
+	// has been expanded on the host.
+	ResourceTypeSCSIFilesystemResize guestrequest.ResourceType = "SCSIFilesystemResize"
 	// ResourceTypeMappedVirtualDisk is the modify resource type for mapped
 	// virtual disks
 	ResourceTypeMappedVirtualDisk guestrequest.ResourceType = "MappedVirtualDisk"
@@ -46,6 +53,21 @@ const (
 	ResourceTypeSecurityPolicy guestrequest.ResourceType = "SecurityPolicy"
 	// ResourceTypePolicyFragment is the modify resource type for injecting policy fragments.
 	ResourceTypePolicyFragment guestrequest.ResourceType = "SecurityPolicyFragment"
+	// ResourceTypeRegistryChanges is the modify resource type for applying
+	// registry value additions and key deletions inside a WCOW utility VM.
+	ResourceTypeRegistryChanges guestrequest.ResourceType = "RegistryChanges"
+	// ResourceTypeGuestFile is the modify resource type for writing small
+	// files directly into the guest.
+	ResourceTypeGuestFile guestrequest.ResourceType = "GuestFile"
+	// ResourceTypeSecret is the modify resource type for delivering secrets
+	// onto guest-local tmpfs storage.
+	ResourceTypeSecret guestrequest.ResourceType = "Secret"
+	// ResourceTypeContainerFreeze is the modify resource type for freezing
+	// and thawing all container processes in the guest.
+	ResourceTypeContainerFreeze guestrequest.ResourceType = "ContainerFreeze"
+	// ResourceTypeTimeSync is the modify resource type for forcing a guest
+	// clock resynchronization.
+	ResourceTypeTimeSync guestrequest.ResourceType = "TimeSync"
 )
 
 // This class is used by a modify request to add or remove a combined layers
@@ -106,6 +128,43 @@ type LCOWMappedDirectory struct {
 	ReadOnly  bool   `json:"ReadOnly,omitempty"`
 }
 
+// SecretDelivery represents a secret to be materialized by the guest as a
+// file named Name under a tmpfs mounted at MountPath, so that the payload
+// never touches host-visible or persistent storage. Payload is base64 encoded
+// over the wire by the JSON marshaller.
+type SecretDelivery struct {
+	Name      string `json:"Name,omitempty"`
+	MountPath string `json:"MountPath,omitempty"`
+	Payload   []byte `json:"Payload,omitempty"`
+}
+
+// ContainerFreeze requests that the guest freeze or thaw all container
+// processes without terminating them. Implemented by the Linux guest using
+// the cgroup freezer (via the runtime's pause/resume); guests without support
+// fail the request with E_NOTIMPL.
+type ContainerFreeze struct {
+	Frozen bool `json:"Frozen"`
+}
+
+// MaxGuestFileTransferSize is the maximum number of file bytes a single
+// GuestFile or ReadFile request may carry. The GCS bridge rejects messages
+// larger than 64KiB, so the bound is chosen to leave room for base64
+// expansion of the contents (4/3) plus the JSON framing and path. It is
+// enforced on both ends: by the host before sending, and by the guest so a
+// reply can never exceed the bridge's message size limit, which would tear
+// down the whole guest connection. Larger transfers must use a mounted
+// share.
+const MaxGuestFileTransferSize = 32 * 1024
+
+// GuestFile represents the contents of a small file to be written at a path
+// inside the guest. Contents is base64 encoded over the wire by the JSON
+// marshaller.
+type GuestFile struct {
+	Path     string `json:"Path,omitempty"`
+	Contents []byte `json:"Contents,omitempty"`
+	Mode     uint32 `json:"Mode,omitempty"`
+}
+
 // LCOWVPMemMappingInfo is one of potentially multiple read-only layers mapped on a VPMem device
 type LCOWVPMemMappingInfo struct {
 	DeviceOffsetInBytes uint64 `json:"DeviceOffsetInBytes,omitempty"`
@@ -165,6 +224,20 @@ type LCOWNetworkAdapter struct {
 	EnableLowMetric    bool   `json:",omitempty"`
 	EncapOverhead      uint16 `json:",omitempty"`
 	VPCIAssigned       bool   `json:",omitempty"`
+	// MTU, if non-zero, is set on the interface explicitly, taking
+	// precedence over the EncapOverhead adjustment.
+	MTU uint16 `json:",omitempty"`
+	// Routes are static routes to program on the interface in addition to
+	// the gateway default routes.
+	Routes []LCOWRoute `json:",omitempty"`
+}
+
+// LCOWRoute is a static route to program on a network adapter's interface in
+// the guest.
+type LCOWRoute struct {
+	NextHop           string `json:",omitempty"`
+	DestinationPrefix string `json:",omitempty"`
+	Metric            uint16 `json:",omitempty"`
 }
 
 type LCOWContainerConstraints struct {
@@ -0,0 +1,20 @@
+//go:build windows
+
+package uvm
+
+import (
+	"context"
+)
+
+// AttestationReport requests a hardware attestation report from the guest,
+// embedding `reportData` (typically a nonce or a hash of a key a relying party
+// will release secrets against) in the signed report. Only meaningful for
+// isolated (SNP) utility VMs where the firmware measures the kernel and
+// initrd; on other configurations the guest rejects the request. Returns
+// `errNotSupported` when there is no guest connection to query.
+func (uvm *UtilityVM) AttestationReport(ctx context.Context, reportData []byte) ([]byte, error) {
+	if uvm.gc == nil {
+		return nil, errNotSupported
+	}
+	return uvm.gc.AttestationReport(ctx, reportData)
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package uvm
+
+import (
+	"context"
+
+	"github.com/Microsoft/hcsshim/internal/protocol/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestresource"
+)
+
+// SyncTime forces a guest clock resynchronization. LCOW guests in particular
+// can drift after a pause/resume or save/restore, and this gives callers a
+// way to correct the jump without restarting the VM. Returns errNotSupported
+// if there is no guest connection.
+func (uvm *UtilityVM) SyncTime(ctx context.Context) error {
+	if uvm.gc == nil {
+		return errNotSupported
+	}
+	req := guestrequest.ModificationRequest{
+		ResourceType: guestresource.ResourceTypeTimeSync,
+		RequestType:  guestrequest.RequestTypeUpdate,
+	}
+	return uvm.GuestRequest(ctx, req)
+}
@@ -113,12 +113,15 @@ func (ca *computeAgent) AddNIC(ctx context.Context, req *computeagent.AddNICInte
 	switch endpt := endpoint.(type) {
 	case *ncproxynetworking.Endpoint:
 		cfg := &guestresource.LCOWNetworkAdapter{
-			NamespaceID:    endpt.NamespaceID,
-			ID:             req.NicID,
-			IPAddress:      endpt.Settings.IPAddress,
-			PrefixLength:   uint8(endpt.Settings.IPAddressPrefixLength),
-			GatewayAddress: endpt.Settings.DefaultGateway,
-			VPCIAssigned:   true,
+			NamespaceID:        endpt.NamespaceID,
+			ID:                 req.NicID,
+			IPAddress:          endpt.Settings.IPAddress,
+			PrefixLength:       uint8(endpt.Settings.IPAddressPrefixLength),
+			GatewayAddress:     endpt.Settings.DefaultGateway,
+			IPv6Address:        endpt.Settings.IPv6Address,
+			IPv6PrefixLength:   uint8(endpt.Settings.IPv6PrefixLength),
+			IPv6GatewayAddress: endpt.Settings.IPv6DefaultGateway,
+			VPCIAssigned:       true,
 		}
 		if err := ca.uvm.AddNICInGuest(ctx, cfg); err != nil {
 			return nil, err
@@ -0,0 +1,58 @@
+//go:build windows
+
+package uvm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Microsoft/hcsshim/internal/hcs/resourcepaths"
+	hcsschema "github.com/Microsoft/hcsshim/internal/hcs/schema2"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestrequest"
+)
+
+// SetSerialConsole attaches or updates the named pipe backing the UVM's COM
+// port `port` (0 is COM1) on a running UVM, so serial output can be captured
+// without recreating the VM.
+func (uvm *UtilityVM) SetSerialConsole(ctx context.Context, port uint32, pipePath string) error {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	req := &hcsschema.ModifySettingRequest{
+		RequestType:  guestrequest.RequestTypeUpdate,
+		ResourcePath: fmt.Sprintf(resourcepaths.SerialResourceFormat, port),
+		Settings: hcsschema.ComPort{
+			NamedPipe: pipePath,
+		},
+	}
+	return uvm.modify(ctx, req)
+}
+
+// RemoveGraphicsConsole removes the keyboard, RDP (enhanced mode video) and
+// video monitor devices from a running UVM that was created with
+// EnableGraphicsConsole, reclaiming their resources once debugging is done.
+// Returns errNotSupported if the UVM wasn't created with a graphics console.
+func (uvm *UtilityVM) RemoveGraphicsConsole(ctx context.Context) error {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	if !uvm.graphicsConsoleEnabled {
+		return errNotSupported
+	}
+
+	for _, resourcePath := range []string{
+		resourcepaths.EnhancedModeVideoResourcePath,
+		resourcepaths.VideoMonitorResourcePath,
+		resourcepaths.KeyboardResourcePath,
+	} {
+		req := &hcsschema.ModifySettingRequest{
+			RequestType:  guestrequest.RequestTypeRemove,
+			ResourcePath: resourcePath,
+		}
+		if err := uvm.modify(ctx, req); err != nil {
+			return fmt.Errorf("failed to remove graphics console device %s: %w", resourcePath, err)
+		}
+	}
+	uvm.graphicsConsoleEnabled = false
+	return nil
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package uvm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	hcsschema "github.com/Microsoft/hcsshim/internal/hcs/schema2"
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+)
+
+// startCrashDumpCollector subscribes to the compute system's lifecycle
+// notifications and, when the guest reports a crash, saves the UVM's state
+// and the crash report into the configured crash dump directory.
+func (uvm *UtilityVM) startCrashDumpCollector(ctx context.Context) error {
+	events, err := uvm.hcsSystem.Subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for event := range events {
+			if event.Type != hcs.SystemEventGuestCrashInitiated && event.Type != hcs.SystemEventGuestCrashed {
+				continue
+			}
+			uvm.collectCrashDump(ctx, event)
+			return
+		}
+	}()
+	return nil
+}
+
+// collectCrashDump saves the UVM's state alongside the crash report carried
+// by the notification. Failures are logged rather than returned; by the time
+// a crash is reported there is no caller to surface them to.
+func (uvm *UtilityVM) collectCrashDump(ctx context.Context, event hcs.SystemEvent) {
+	e := log.G(ctx).WithField(logfields.UVMID, uvm.id)
+
+	if event.Data != "" {
+		reportPath := filepath.Join(uvm.crashDumpDirectory, fmt.Sprintf("%s-crashreport.json", uvm.id))
+		if err := os.WriteFile(reportPath, []byte(event.Data), 0644); err != nil {
+			e.WithError(err).Error("failed to write guest crash report")
+		} else {
+			e.WithField("path", reportPath).Info("wrote guest crash report")
+		}
+	}
+
+	dumpPath := filepath.Join(uvm.crashDumpDirectory, fmt.Sprintf("%s-crashdump.vmrs", uvm.id))
+	if err := uvm.hcsSystem.Save(ctx, &hcsschema.SaveOptions{
+		SaveType:          "ToFile",
+		SaveStateFilePath: dumpPath,
+	}); err != nil {
+		e.WithError(err).Error("failed to save guest crash dump")
+		return
+	}
+	e.WithField("path", dumpPath).Info("saved guest crash dump")
+}
@@ -61,6 +61,16 @@ type Options struct {
 	// when scheduling. If `0` will default to platform default.
 	ProcessorWeight int32
 
+	// PreferredPhysicalNumaNodes are the host NUMA nodes the VM's memory and
+	// processors should be allocated from, in order of preference. If empty
+	// the platform places the VM.
+	PreferredPhysicalNumaNodes []int64
+
+	// NumaSettings explicitly splits the VM into virtual NUMA nodes, each
+	// pinned to a physical node with its own processor count and memory-block
+	// split. If empty the platform decides the virtual topology.
+	NumaSettings []hcsschema.NumaSetting
+
 	// StorageQoSIopsMaximum sets the maximum number of Iops. If `0` will
 	// default to the platform default.
 	StorageQoSIopsMaximum int32
@@ -103,14 +113,64 @@ type Options struct {
 	// DumpDirectoryPath is the path of the directory inside which all debug dumps etc are stored.
 	DumpDirectoryPath string
 
+	// CrashDumpDirectory is an optional directory on the host in which the
+	// UVM's state is saved when the guest reports a crash, along with the
+	// crash report delivered with the notification. If empty no dump is
+	// collected.
+	CrashDumpDirectory string
+
 	// 	AdditionalHyperVConfig are extra Hyper-V socket configurations to provide.
 	AdditionalHyperVConfig map[string]hcsschema.HvSocketServiceConfig
+
+	// SchemaVersion overrides the default schema version used in the create
+	// document, after validation against the platform's supported range. If
+	// nil the platform default is used. This allows pinning a schema version
+	// to reproduce behavior on older hosts.
+	SchemaVersion *hcsschema.Version
+
+	// TimeSource configures the virtual machine's clock source. Valid values
+	// are TimeSourceHostSynced (the default) and TimeSourceIndependent, for
+	// workloads that must not observe host clock adjustments. An empty value
+	// leaves the platform default in place.
+	TimeSource string
+}
+
+// Valid values for Options.TimeSource.
+const (
+	TimeSourceHostSynced  = "HostSynced"
+	TimeSourceIndependent = "Independent"
+)
+
+// documentSchemaVersion returns the schema version to use in a create
+// document: the caller-requested override when set, otherwise the default.
+func documentSchemaVersion(requested, def *hcsschema.Version) *hcsschema.Version {
+	if requested != nil {
+		return requested
+	}
+	return def
+}
+
+// verifyTimeSource validates an Options.TimeSource value.
+func verifyTimeSource(timeSource string) error {
+	switch timeSource {
+	case "", TimeSourceHostSynced, TimeSourceIndependent:
+		return nil
+	}
+	return fmt.Errorf("unknown TimeSource value: %s", timeSource)
 }
 
 // Verifies that the final UVM options are correct and supported.
 func verifyOptions(_ context.Context, options interface{}) error {
 	switch opts := options.(type) {
 	case *OptionsLCOW:
+		if opts.SchemaVersion != nil {
+			if err := schemaversion.IsSupported(opts.SchemaVersion); err != nil {
+				return err
+			}
+		}
+		if err := verifyTimeSource(opts.TimeSource); err != nil {
+			return err
+		}
 		if opts.EnableDeferredCommit && !opts.AllowOvercommit {
 			return errors.New("EnableDeferredCommit is not supported on physically backed VMs")
 		}
@@ -133,6 +193,14 @@ func verifyOptions(_ context.Context, options interface{}) error {
 			return errors.New("EnableColdDiscardHint is not supported on builds older than 18967")
 		}
 	case *OptionsWCOW:
+		if opts.SchemaVersion != nil {
+			if err := schemaversion.IsSupported(opts.SchemaVersion); err != nil {
+				return err
+			}
+		}
+		if err := verifyTimeSource(opts.TimeSource); err != nil {
+			return err
+		}
 		if opts.EnableDeferredCommit && !opts.AllowOvercommit {
 			return errors.New("EnableDeferredCommit is not supported on physically backed VMs")
 		}
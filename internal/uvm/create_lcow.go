@@ -10,10 +10,13 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/Microsoft/go-winio"
 	"github.com/Microsoft/go-winio/pkg/guid"
+	"golang.org/x/sys/windows"
+
 	"github.com/Microsoft/hcsshim/pkg/securitypolicy"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -125,6 +128,7 @@ type OptionsLCOW struct {
 	VPMemDeviceCount        uint32               // Number of VPMem devices. Defaults to `DefaultVPMEMCount`. Limit at 128. If booting UVM from VHD, device 0 is taken.
 	VPMemSizeBytes          uint64               // Size of the VPMem devices. Defaults to `DefaultVPMemSizeBytes`.
 	VPMemNoMultiMapping     bool                 // Disables LCOW layer multi mapping
+	VPMemNoFallback         bool                 // Disables falling back to SCSI for layers when no VPMem slots are available
 	PreferredRootFSType     PreferredRootFSType  // If `KernelFile` is `InitrdFile` use `PreferredRootFSTypeInitRd`. If `KernelFile` is `VhdFile` use `PreferredRootFSTypeVHD`
 	EnableColdDiscardHint   bool                 // Whether the HCS should use cold discard hints. Defaults to false
 	VPCIEnabled             bool                 // Whether the kernel should enable pci
@@ -133,6 +137,19 @@ type OptionsLCOW struct {
 	HclEnabled              *bool                // Whether to enable the host compatibility layer
 	ExtraVSockPorts         []uint32             // Extra vsock ports to allow
 	AssignedDevices         []VPCIDeviceID       // AssignedDevices are devices to add on pod boot
+	BootVPMemDevices        []BootVPMemDevice    // Additional read-only VPMem devices attached in the create document rather than hot-added after boot
+	RequireLocalBootFiles   bool                 // Fail creation if `BootFilesPath` is on a network path rather than just logging a warning
+}
+
+// BootVPMemDevice describes a read-only VPMem device to include in the
+// initial create document for an LCOW utility VM, avoiding a hot-add round
+// trip for layers known to be needed at boot.
+type BootVPMemDevice struct {
+	// HostPath is the host path of the VHD(X) backing the device.
+	HostPath string
+	// UVMPath is the guest path recorded for the device. If empty a default
+	// layer path is assigned.
+	UVMPath string
 }
 
 // defaultLCOWOSBootFilesPath returns the default path used to locate the LCOW
@@ -407,7 +424,9 @@ func makeLCOWVMGSDoc(ctx context.Context, opts *OptionsLCOW, uvm *UtilityVM) (_
 		ShouldTerminateOnLastHandleClosed: true,
 		VirtualMachine: &hcsschema.VirtualMachine{
 			StopOnReset: true,
-			Chipset:     &hcsschema.Chipset{},
+			Chipset: &hcsschema.Chipset{
+				TimeSource: opts.TimeSource,
+			},
 			ComputeTopology: &hcsschema.Topology{
 				Memory: &hcsschema.Memory2{
 					SizeInMB:              memorySizeInMB,
@@ -419,6 +438,7 @@ func makeLCOWVMGSDoc(ctx context.Context, opts *OptionsLCOW, uvm *UtilityVM) (_
 					HighMMIOGapInMB:       opts.HighMMIOGapInMB,
 				},
 				Processor: processor,
+				Numa:      opts.numaTopology(),
 			},
 			Devices: &hcsschema.Devices{
 				HvSocket: &hcsschema.HvSocket2{
@@ -586,10 +606,145 @@ Example JSON document produced once the hcsschema.ComputeSytem returned by makeL
 }
 */
 
+// kernelCommandLineArgs computes the kernel command line that makeLCOWDoc
+// emits for `opts` and a UVM with `vpmemMaxCount` VPMem devices. It has no
+// side effects so it can also be used for dry-run inspection of the boot
+// line.
+func kernelCommandLineArgs(opts *OptionsLCOW, vpmemMaxCount uint32) (string, error) {
+	var kernelArgs string
+	switch opts.PreferredRootFSType {
+	case PreferredRootFSTypeInitRd:
+		if !opts.KernelDirect {
+			kernelArgs = "initrd=/" + opts.RootFSFile
+		}
+	case PreferredRootFSTypeVHD:
+		if vpmemMaxCount > 0 {
+			kernelArgs = "root=/dev/pmem0 ro rootwait init=/init"
+		} else {
+			kernelArgs = "root=/dev/sda ro rootwait init=/init"
+			if opts.DmVerityMode {
+				if len(opts.DmVerityCreateArgs) == 0 {
+					return "", errors.New("DmVerityCreateArgs must be set when DmVerityMode is true and not booting from a vmgs file.")
+				}
+				kernelArgs = fmt.Sprintf("root=/dev/dm-0 dm-mod.create=%q init=/init", opts.DmVerityCreateArgs)
+			}
+		}
+	}
+
+	vmDebugging := false
+	if opts.ConsolePipe != "" {
+		vmDebugging = true
+		kernelArgs += " 8250_core.nr_uarts=1 8250_core.skip_txen_test=1 console=ttyS0,115200"
+	} else {
+		kernelArgs += " 8250_core.nr_uarts=0"
+	}
+
+	if opts.EnableGraphicsConsole {
+		vmDebugging = true
+		kernelArgs += " console=tty"
+	}
+
+	if !vmDebugging {
+		// Terminate the VM if there is a kernel panic.
+		kernelArgs += " panic=-1 quiet"
+	}
+
+	// Add Kernel Boot options
+	if opts.KernelBootOptions != "" {
+		kernelArgs += " " + opts.KernelBootOptions
+	}
+
+	if !opts.VPCIEnabled {
+		kernelArgs += ` pci=off`
+	}
+
+	// Inject initial entropy over vsock during init launch.
+	entropyArgs := fmt.Sprintf("-e %d", entropyVsockPort)
+
+	// With default options, run GCS with stderr pointing to the vsock port
+	// created at VM creation in order to forward guest logs to logrus.
+	execCmdArgs := "/bin/vsockexec"
+
+	if opts.ForwardStdout {
+		execCmdArgs += fmt.Sprintf(" -o %d", linuxLogVsockPort)
+	}
+
+	if opts.ForwardStderr {
+		execCmdArgs += fmt.Sprintf(" -e %d", linuxLogVsockPort)
+	}
+
+	execCommandLine := opts.ExecCommandLine
+	if opts.DisableTimeSyncService {
+		execCommandLine = fmt.Sprintf("%s -disable-time-sync", execCommandLine)
+	}
+
+	if log.IsScrubbingEnabled() {
+		execCommandLine += " -scrub-logs"
+	}
+
+	execCmdArgs += " " + execCommandLine
+
+	if opts.ProcessDumpLocation != "" {
+		execCmdArgs += " -core-dump-location " + opts.ProcessDumpLocation
+	}
+
+	initArgs := fmt.Sprintf("%s %s", entropyArgs, execCmdArgs)
+	if vmDebugging {
+		// Launch a shell on the console.
+		initArgs = entropyArgs + ` sh -c "` + execCmdArgs + ` & exec sh"`
+	}
+
+	kernelArgs += fmt.Sprintf(" nr_cpus=%d", opts.ProcessorCount)
+	kernelArgs += ` brd.rd_nr=0 pmtmr=0 -- ` + initArgs
+	return kernelArgs, nil
+}
+
+// KernelCommandLine returns the exact kernel command line that CreateLCOW
+// would use for `opts`, without creating the VM. This lets callers and tests
+// verify that boot options are composed correctly before committing to a
+// create. It only covers the non-SNP boot path.
+func KernelCommandLine(opts *OptionsLCOW) (string, error) {
+	return kernelCommandLineArgs(opts, opts.VPMemDeviceCount)
+}
+
+// isNetworkPath returns true if `path` resides on network storage: either a
+// UNC path or a drive letter mapped to a remote share.
+func isNetworkPath(path string) bool {
+	if strings.HasPrefix(path, `\\`) {
+		return true
+	}
+	vol := filepath.VolumeName(path)
+	if len(vol) != 2 || vol[1] != ':' {
+		return false
+	}
+	root, err := windows.UTF16PtrFromString(vol + `\`)
+	if err != nil {
+		return false
+	}
+	return windows.GetDriveType(root) == windows.DRIVE_REMOTE
+}
+
+// checkBootFilesLocality warns when the LCOW boot files live on a network
+// path, which causes hard-to-attribute multi-second boot stalls, or errors if
+// `opts.RequireLocalBootFiles` is set.
+func checkBootFilesLocality(ctx context.Context, opts *OptionsLCOW) error {
+	if !isNetworkPath(opts.BootFilesPath) {
+		return nil
+	}
+	if opts.RequireLocalBootFiles {
+		return fmt.Errorf("boot files path '%s' is on a network path and local boot files are required", opts.BootFilesPath)
+	}
+	log.G(ctx).WithField("bootFilesPath", opts.BootFilesPath).Warn("LCOW boot files are on a network path; booting the utility VM from network storage can stall for multiple seconds")
+	return nil
+}
+
 // Make the ComputeSystem document object that will be serialized to json to be presented to the HCS api.
 func makeLCOWDoc(ctx context.Context, opts *OptionsLCOW, uvm *UtilityVM) (_ *hcsschema.ComputeSystem, err error) {
 	logrus.Tracef("makeLCOWDoc %v\n", opts)
 
+	if err := checkBootFilesLocality(ctx, opts); err != nil {
+		return nil, err
+	}
 	kernelFullPath := filepath.Join(opts.BootFilesPath, opts.KernelFile)
 	if _, err := os.Stat(kernelFullPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("kernel: '%s' not found", kernelFullPath)
@@ -610,11 +765,13 @@ func makeLCOWDoc(ctx context.Context, opts *OptionsLCOW, uvm *UtilityVM) (_ *hcs
 
 	doc := &hcsschema.ComputeSystem{
 		Owner:                             uvm.owner,
-		SchemaVersion:                     schemaversion.SchemaV21(),
+		SchemaVersion:                     documentSchemaVersion(opts.SchemaVersion, schemaversion.SchemaV21()),
 		ShouldTerminateOnLastHandleClosed: true,
 		VirtualMachine: &hcsschema.VirtualMachine{
 			StopOnReset: true,
-			Chipset:     &hcsschema.Chipset{},
+			Chipset: &hcsschema.Chipset{
+				TimeSource: opts.TimeSource,
+			},
 			ComputeTopology: &hcsschema.Topology{
 				Memory: &hcsschema.Memory2{
 					SizeInMB:              memorySizeInMB,
@@ -626,6 +783,7 @@ func makeLCOWDoc(ctx context.Context, opts *OptionsLCOW, uvm *UtilityVM) (_ *hcs
 					HighMMIOGapInMB:       opts.HighMMIOGapInMB,
 				},
 				Processor: processor,
+				Numa:      opts.numaTopology(),
 			},
 			Devices: &hcsschema.Devices{
 				HvSocket: &hcsschema.HvSocket2{
@@ -705,16 +863,9 @@ func makeLCOWDoc(ctx context.Context, opts *OptionsLCOW, uvm *UtilityVM) (_ *hcs
 		}
 	}
 
-	var kernelArgs string
-	switch opts.PreferredRootFSType {
-	case PreferredRootFSTypeInitRd:
-		if !opts.KernelDirect {
-			kernelArgs = "initrd=/" + opts.RootFSFile
-		}
-	case PreferredRootFSTypeVHD:
+	if opts.PreferredRootFSType == PreferredRootFSTypeVHD {
 		if uvm.vpmemMaxCount > 0 {
 			// Support for VPMem VHD(X) booting rather than initrd..
-			kernelArgs = "root=/dev/pmem0 ro rootwait init=/init"
 			imageFormat := "Vhd1"
 			if strings.ToLower(filepath.Ext(opts.RootFSFile)) == "vhdx" {
 				imageFormat = "Vhdx"
@@ -757,13 +908,6 @@ func makeLCOWDoc(ctx context.Context, opts *OptionsLCOW, uvm *UtilityVM) (_ *hcs
 				uvm.vpmemDevicesDefault[0] = dev
 			}
 		} else {
-			kernelArgs = "root=/dev/sda ro rootwait init=/init"
-			if opts.DmVerityMode {
-				if len(opts.DmVerityCreateArgs) == 0 {
-					return nil, errors.New("DmVerityCreateArgs must be set when DmVerityMode is true and not booting from a vmgs file.")
-				}
-				kernelArgs = fmt.Sprintf("root=/dev/dm-0 dm-mod.create=%q init=/init", opts.DmVerityCreateArgs)
-			}
 			doc.VirtualMachine.Devices.Scsi[guestrequest.ScsiControllerGuids[0]].Attachments["0"] = hcsschema.Attachment{
 				Type_:    "VirtualDisk",
 				Path:     rootfsFullPath,
@@ -773,79 +917,79 @@ func makeLCOWDoc(ctx context.Context, opts *OptionsLCOW, uvm *UtilityVM) (_ *hcs
 		}
 	}
 
-	vmDebugging := false
+	if len(opts.BootVPMemDevices) > 0 {
+		if uvm.vpmemMaxCount == 0 {
+			return nil, errors.New("BootVPMemDevices specified but VPMem is disabled for this utility VM")
+		}
+		if doc.VirtualMachine.Devices.VirtualPMem.Devices == nil {
+			doc.VirtualMachine.Devices.VirtualPMem.Devices = map[string]hcsschema.VirtualPMemDevice{}
+		}
+		// Device 0 may already be taken by a VHD rootfs boot.
+		deviceNumber := uint32(len(doc.VirtualMachine.Devices.VirtualPMem.Devices))
+		if deviceNumber+uint32(len(opts.BootVPMemDevices)) > uvm.vpmemMaxCount {
+			return nil, fmt.Errorf("%d boot VPMem devices exceed the maximum of %d for this utility VM", len(opts.BootVPMemDevices), uvm.vpmemMaxCount)
+		}
+		for _, bootDev := range opts.BootVPMemDevices {
+			st, stErr := os.Stat(bootDev.HostPath)
+			if stErr != nil {
+				return nil, errors.Wrapf(stErr, "failed to stat boot VPMem device: %q", bootDev.HostPath)
+			}
+			if uint64(st.Size()) > uvm.vpmemMaxSizeBytes {
+				return nil, ErrMaxVPMemLayerSize
+			}
+			uvmPath := bootDev.UVMPath
+			if uvmPath == "" {
+				uvmPath = fmt.Sprintf(lcowDefaultVPMemLayerFmt, deviceNumber)
+			}
+			imageFormat := "Vhd1"
+			if strings.ToLower(filepath.Ext(bootDev.HostPath)) == ".vhdx" {
+				imageFormat = "Vhdx"
+			}
+			doc.VirtualMachine.Devices.VirtualPMem.Devices[strconv.FormatUint(uint64(deviceNumber), 10)] = hcsschema.VirtualPMemDevice{
+				HostPath:    bootDev.HostPath,
+				ReadOnly:    true,
+				ImageFormat: imageFormat,
+			}
+			if uvm.vpmemMultiMapping {
+				pmem := newPackedVPMemDevice()
+				pmem.maxMappedDeviceCount = 1
+
+				devSize := pageAlign(uint64(st.Size()))
+				memReg, pErr := pmem.Allocate(devSize)
+				if pErr != nil {
+					return nil, errors.Wrapf(pErr, "failed to allocate memory for boot VPMem device: %q", bootDev.HostPath)
+				}
+				dev := newVPMemMappedDevice(bootDev.HostPath, uvmPath, devSize, memReg)
+				if err := pmem.mapVHDLayer(ctx, dev); err != nil {
+					return nil, errors.Wrapf(err, "failed to save internal state for boot VPMem device: %q", bootDev.HostPath)
+				}
+				uvm.vpmemDevicesMultiMapped[deviceNumber] = pmem
+			} else {
+				uvm.vpmemDevicesDefault[deviceNumber] = newDefaultVPMemInfo(bootDev.HostPath, uvmPath)
+			}
+			deviceNumber++
+		}
+	}
+
 	if opts.ConsolePipe != "" {
-		vmDebugging = true
-		kernelArgs += " 8250_core.nr_uarts=1 8250_core.skip_txen_test=1 console=ttyS0,115200"
 		doc.VirtualMachine.Devices.ComPorts = map[string]hcsschema.ComPort{
 			"0": { // Which is actually COM1
 				NamedPipe: opts.ConsolePipe,
 			},
 		}
-	} else {
-		kernelArgs += " 8250_core.nr_uarts=0"
 	}
 
 	if opts.EnableGraphicsConsole {
-		vmDebugging = true
-		kernelArgs += " console=tty"
 		doc.VirtualMachine.Devices.Keyboard = &hcsschema.Keyboard{}
 		doc.VirtualMachine.Devices.EnhancedModeVideo = &hcsschema.EnhancedModeVideo{}
 		doc.VirtualMachine.Devices.VideoMonitor = &hcsschema.VideoMonitor{}
 	}
 
-	if !vmDebugging {
-		// Terminate the VM if there is a kernel panic.
-		kernelArgs += " panic=-1 quiet"
-	}
-
-	// Add Kernel Boot options
-	if opts.KernelBootOptions != "" {
-		kernelArgs += " " + opts.KernelBootOptions
-	}
-
-	if !opts.VPCIEnabled {
-		kernelArgs += ` pci=off`
-	}
-
-	// Inject initial entropy over vsock during init launch.
-	entropyArgs := fmt.Sprintf("-e %d", entropyVsockPort)
-
-	// With default options, run GCS with stderr pointing to the vsock port
-	// created below in order to forward guest logs to logrus.
-	execCmdArgs := "/bin/vsockexec"
-
-	if opts.ForwardStdout {
-		execCmdArgs += fmt.Sprintf(" -o %d", linuxLogVsockPort)
-	}
-
-	if opts.ForwardStderr {
-		execCmdArgs += fmt.Sprintf(" -e %d", linuxLogVsockPort)
-	}
-
-	if opts.DisableTimeSyncService {
-		opts.ExecCommandLine = fmt.Sprintf("%s -disable-time-sync", opts.ExecCommandLine)
-	}
-
-	if log.IsScrubbingEnabled() {
-		opts.ExecCommandLine += " -scrub-logs"
-	}
-
-	execCmdArgs += " " + opts.ExecCommandLine
-
-	if opts.ProcessDumpLocation != "" {
-		execCmdArgs += " -core-dump-location " + opts.ProcessDumpLocation
-	}
-
-	initArgs := fmt.Sprintf("%s %s", entropyArgs, execCmdArgs)
-	if vmDebugging {
-		// Launch a shell on the console.
-		initArgs = entropyArgs + ` sh -c "` + execCmdArgs + ` & exec sh"`
+	kernelArgs, err := kernelCommandLineArgs(opts, uvm.vpmemMaxCount)
+	if err != nil {
+		return nil, err
 	}
 
-	kernelArgs += fmt.Sprintf(" nr_cpus=%d", opts.ProcessorCount)
-	kernelArgs += ` brd.rd_nr=0 pmtmr=0 -- ` + initArgs
-
 	if !opts.KernelDirect {
 		doc.VirtualMachine.Chipset.Uefi = &hcsschema.Uefi{
 			BootThis: &hcsschema.UefiBootEntry{
@@ -903,9 +1047,12 @@ func CreateLCOW(ctx context.Context, opts *OptionsLCOW) (_ *UtilityVM, err error
 		devicesPhysicallyBacked: opts.FullyPhysicallyBacked,
 		createOpts:              opts,
 		vpmemMultiMapping:       !opts.VPMemNoMultiMapping,
+		vpmemNoFallback:         opts.VPMemNoFallback,
 		encryptScratch:          opts.EnableScratchEncryption,
 		noWritableFileShares:    opts.NoWritableFileShares,
 		confidentialUVMOptions:  opts.ConfidentialOptions,
+		graphicsConsoleEnabled:  opts.EnableGraphicsConsole,
+		crashDumpDirectory:      opts.CrashDumpDirectory,
 	}
 
 	defer func() {
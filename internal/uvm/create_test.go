@@ -4,6 +4,8 @@ package uvm
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -19,3 +21,22 @@ func TestCreateBadBootFilesPath(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestKernelCommandLine(t *testing.T) {
+	opts := NewDefaultOptionsLCOW(t.Name(), "")
+	opts.KernelBootOptions = "custom=1"
+
+	cmdline, err := KernelCommandLine(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(cmdline, "custom=1") {
+		t.Fatalf("expected kernel boot options in command line: %s", cmdline)
+	}
+	if strings.Count(cmdline, "console=") > 1 {
+		t.Fatalf("expected at most one console= entry in command line: %s", cmdline)
+	}
+	if !strings.Contains(cmdline, fmt.Sprintf("nr_cpus=%d", opts.ProcessorCount)) {
+		t.Fatalf("expected processor count in command line: %s", cmdline)
+	}
+}
@@ -40,8 +40,16 @@ type OptionsWCOW struct {
 	// NoInheritHostTimezone specifies whether to not inherit the hosts timezone for the UVM. UTC will be set as the default for the VM instead.
 	NoInheritHostTimezone bool
 
+	// Timezone is the name of a Windows time zone (for example "Pacific Standard Time") to set in the UVM.
+	// If set it takes precedence over NoInheritHostTimezone and the host's timezone.
+	Timezone string
+
 	// AdditionalRegistryKeys are Registry keys and their values to additionally add to the uVM.
 	AdditionalRegistryKeys []hcsschema.RegistryValue
+
+	// ConsolePipes maps COM port indexes (0 is COM1) to named pipe paths
+	// (eg \\.\pipe\vmpipe) that receive the UVM's serial output.
+	ConsolePipes map[uint32]string
 }
 
 // NewDefaultOptionsWCOW creates the default options for a bootable version of
@@ -166,11 +174,12 @@ func prepareConfigDoc(ctx context.Context, uvm *UtilityVM, opts *OptionsWCOW) (*
 
 	doc := &hcsschema.ComputeSystem{
 		Owner:                             uvm.owner,
-		SchemaVersion:                     schemaversion.SchemaV21(),
+		SchemaVersion:                     documentSchemaVersion(opts.SchemaVersion, schemaversion.SchemaV21()),
 		ShouldTerminateOnLastHandleClosed: true,
 		VirtualMachine: &hcsschema.VirtualMachine{
 			StopOnReset: true,
 			Chipset: &hcsschema.Chipset{
+				TimeSource: opts.TimeSource,
 				Uefi: &hcsschema.Uefi{
 					BootThis: &hcsschema.UefiBootEntry{
 						DevicePath: filepath.Join(opts.BootFiles.OSRelativeBootDirPath, "bootmgfw.efi"),
@@ -191,6 +200,7 @@ func prepareConfigDoc(ctx context.Context, uvm *UtilityVM, opts *OptionsWCOW) (*
 					HighMMIOGapInMB:      opts.HighMMIOGapInMB,
 				},
 				Processor: processor,
+				Numa:      opts.numaTopology(),
 			},
 			Devices: &hcsschema.Devices{
 				HvSocket: &hcsschema.HvSocket2{
@@ -208,6 +218,14 @@ func prepareConfigDoc(ctx context.Context, uvm *UtilityVM, opts *OptionsWCOW) (*
 
 	maps.Copy(doc.VirtualMachine.Devices.HvSocket.HvSocketConfig.ServiceTable, opts.AdditionalHyperVConfig)
 
+	if len(opts.ConsolePipes) > 0 {
+		comPorts := make(map[string]hcsschema.ComPort, len(opts.ConsolePipes))
+		for port, pipe := range opts.ConsolePipes {
+			comPorts[fmt.Sprintf("%d", port)] = hcsschema.ComPort{NamedPipe: pipe}
+		}
+		doc.VirtualMachine.Devices.ComPorts = comPorts
+	}
+
 	// Handle StorageQoS if set
 	if opts.StorageQoSBandwidthMaximum > 0 || opts.StorageQoSIopsMaximum > 0 {
 		doc.VirtualMachine.StorageQoS = &hcsschema.StorageQoS{
@@ -265,10 +283,12 @@ func CreateWCOW(ctx context.Context, opts *OptionsWCOW) (_ *UtilityVM, err error
 		vsmbFileShares:          make(map[string]*VSMBShare),
 		vpciDevices:             make(map[VPCIDeviceID]*VPCIDevice),
 		noInheritHostTimezone:   opts.NoInheritHostTimezone,
+		timezone:                opts.Timezone,
 		physicallyBacked:        !opts.AllowOvercommit,
 		devicesPhysicallyBacked: opts.FullyPhysicallyBacked,
 		vsmbNoDirectMap:         opts.NoDirectMap,
 		noWritableFileShares:    opts.NoWritableFileShares,
+		crashDumpDirectory:      opts.CrashDumpDirectory,
 		createOpts:              *opts,
 	}
 
@@ -0,0 +1,145 @@
+//go:build windows
+
+package uvm
+
+import (
+	"fmt"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/hcs/schema2"
+)
+
+// VSMBShareEntry is a snapshot of a single vSMB share in a DeviceState.
+type VSMBShareEntry struct {
+	HostPath     string
+	Name         string
+	GuestPath    string
+	AllowedFiles []string
+	RefCount     uint32
+	IsDirShare   bool
+	Options      hcsschema.VirtualSmbShareOptions
+}
+
+// VPMemDeviceEntry is a snapshot of a single VPMem device in a DeviceState.
+type VPMemDeviceEntry struct {
+	DeviceNumber uint32
+	HostPath     string
+	UVMPath      string
+	RefCount     uint32
+}
+
+// DeviceState is a wholesale snapshot of the UVM's in-memory device
+// tracking, produced by ExportDeviceState and applied by SetDeviceState.
+//
+// SCSI attachments are owned by the SCSI manager and are not part of this
+// snapshot.
+type DeviceState struct {
+	VSMBShares   []VSMBShareEntry
+	VSMBCounter  uint64
+	VPMemDevices []VPMemDeviceEntry
+}
+
+// ExportDeviceState returns a snapshot of the UVM's in-memory vSMB and VPMem
+// device maps, suitable for persisting in an external store and later
+// reapplying with SetDeviceState.
+func (uvm *UtilityVM) ExportDeviceState() DeviceState {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	state := DeviceState{
+		VSMBCounter: uvm.vsmbCounter,
+	}
+	for _, m := range []map[string]*VSMBShare{uvm.vsmbDirShares, uvm.vsmbFileShares} {
+		for _, share := range m {
+			state.VSMBShares = append(state.VSMBShares, VSMBShareEntry{
+				HostPath:     share.HostPath,
+				Name:         share.name,
+				GuestPath:    share.guestPath,
+				AllowedFiles: share.allowedFiles,
+				RefCount:     share.refCount,
+				IsDirShare:   share.isDirShare,
+				Options:      share.options,
+			})
+		}
+	}
+	for i := uint32(0); i < uvm.vpmemMaxCount; i++ {
+		if dev := uvm.vpmemDevicesDefault[i]; dev != nil {
+			state.VPMemDevices = append(state.VPMemDevices, VPMemDeviceEntry{
+				DeviceNumber: i,
+				HostPath:     dev.hostPath,
+				UVMPath:      dev.uvmPath,
+				RefCount:     dev.refCount,
+			})
+		}
+	}
+	return state
+}
+
+// SetDeviceState replaces the UVM's in-memory vSMB and VPMem device maps
+// wholesale with `state`. It is the counterpart to ExportDeviceState and is
+// intended for use after OpenUtilityVM when an external reconciliation has
+// determined the authoritative device state. The entire snapshot is
+// validated before any map is touched, so a failed call leaves the UVM
+// unchanged.
+func (uvm *UtilityVM) SetDeviceState(state DeviceState) error {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	dirShares := make(map[string]*VSMBShare)
+	fileShares := make(map[string]*VSMBShare)
+	for _, entry := range state.VSMBShares {
+		if entry.HostPath == "" {
+			return fmt.Errorf("vSMB share entry is missing a host path")
+		}
+		if entry.RefCount == 0 {
+			return fmt.Errorf("vSMB share entry for %s has a zero refcount", entry.HostPath)
+		}
+		m := dirShares
+		if !entry.IsDirShare {
+			m = fileShares
+		}
+		shareKey := getVSMBShareKey(entry.HostPath, entry.Options.ReadOnly)
+		if _, ok := m[shareKey]; ok {
+			return fmt.Errorf("duplicate vSMB share entry for %s", entry.HostPath)
+		}
+		m[shareKey] = &VSMBShare{
+			vm:           uvm,
+			HostPath:     entry.HostPath,
+			refCount:     entry.RefCount,
+			name:         entry.Name,
+			allowedFiles: entry.AllowedFiles,
+			guestPath:    entry.GuestPath,
+			options:      entry.Options,
+			isDirShare:   entry.IsDirShare,
+		}
+	}
+
+	var vpmemDevices [MaxVPMEMCount]*vPMemInfoDefault
+	if len(state.VPMemDevices) > 0 && uvm.vpmemMultiMapping {
+		return fmt.Errorf("cannot set VPMem device state on a UVM using multi-mapped VPMem devices: %w", errNotSupported)
+	}
+	for _, entry := range state.VPMemDevices {
+		if entry.DeviceNumber >= uvm.vpmemMaxCount {
+			return fmt.Errorf("VPMem device number %d exceeds the maximum of %d for this utility VM", entry.DeviceNumber, uvm.vpmemMaxCount)
+		}
+		if entry.HostPath == "" {
+			return fmt.Errorf("VPMem device entry %d is missing a host path", entry.DeviceNumber)
+		}
+		if entry.RefCount == 0 {
+			return fmt.Errorf("VPMem device entry for %s has a zero refcount", entry.HostPath)
+		}
+		if vpmemDevices[entry.DeviceNumber] != nil {
+			return fmt.Errorf("duplicate VPMem device entry for device number %d", entry.DeviceNumber)
+		}
+		vpmemDevices[entry.DeviceNumber] = &vPMemInfoDefault{
+			hostPath: entry.HostPath,
+			uvmPath:  entry.UVMPath,
+			refCount: entry.RefCount,
+		}
+	}
+
+	uvm.vsmbDirShares = dirShares
+	uvm.vsmbFileShares = fileShares
+	uvm.vsmbCounter = state.VSMBCounter
+	uvm.vpmemDevicesDefault = vpmemDevices
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build windows
+
+package uvm
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/protocol/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestresource"
+)
+
+// MaxGuestFileTransferSize is the maximum number of bytes that WriteGuestFile
+// or ReadGuestFile will transfer in a single request; it is sized so the
+// encoded message fits within the GCS bridge's message size limit. Larger
+// transfers should use a mounted share (e.g. `Share`) instead.
+const MaxGuestFileTransferSize = guestresource.MaxGuestFileTransferSize
+
+// WriteGuestFile writes `data` to `guestPath` inside the UVM with file mode
+// `mode`, without spawning a guest process. It is intended for small
+// configuration files (CNI results, config snippets); transfers larger than
+// MaxGuestFileTransferSize are rejected.
+func (uvm *UtilityVM) WriteGuestFile(ctx context.Context, guestPath string, data []byte, mode os.FileMode) error {
+	if uvm.gc == nil {
+		return errNotSupported
+	}
+	if guestPath == "" {
+		return fmt.Errorf("guest path must be specified")
+	}
+	if len(data) > MaxGuestFileTransferSize {
+		return fmt.Errorf("%d byte transfer to %s exceeds the %d byte guest file limit, use a mounted share instead", len(data), guestPath, MaxGuestFileTransferSize)
+	}
+	req := guestrequest.ModificationRequest{
+		ResourceType: guestresource.ResourceTypeGuestFile,
+		RequestType:  guestrequest.RequestTypeAdd,
+		Settings: &guestresource.GuestFile{
+			Path:     guestPath,
+			Contents: data,
+			Mode:     uint32(mode),
+		},
+	}
+	return uvm.GuestRequest(ctx, req)
+}
+
+// ReadGuestFile reads the contents of `guestPath` inside the UVM without
+// spawning a guest process. It is subject to the same size bound as
+// WriteGuestFile; reads of larger files should use a mounted share.
+func (uvm *UtilityVM) ReadGuestFile(ctx context.Context, guestPath string) ([]byte, error) {
+	if uvm.gc == nil {
+		return nil, errNotSupported
+	}
+	if guestPath == "" {
+		return nil, fmt.Errorf("guest path must be specified")
+	}
+	data, err := uvm.gc.ReadFile(ctx, guestPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxGuestFileTransferSize {
+		return nil, fmt.Errorf("%d byte transfer from %s exceeds the %d byte guest file limit, use a mounted share instead", len(data), guestPath, MaxGuestFileTransferSize)
+	}
+	return data, nil
+}
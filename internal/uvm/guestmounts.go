@@ -0,0 +1,24 @@
+//go:build windows
+
+package uvm
+
+import (
+	"context"
+
+	"github.com/Microsoft/hcsshim/internal/gcs"
+)
+
+// GuestMount describes a single active mount inside the guest.
+type GuestMount = gcs.GuestMount
+
+// ListGuestMounts enumerates the mounts currently active inside the guest
+// (mount path, source device and whether the mount is read-only). Callers can
+// cross-check the result against the shim's in-memory device maps after a
+// reattach to detect and repair discrepancies. Returns `errNotSupported` when
+// there is no guest connection to query.
+func (uvm *UtilityVM) ListGuestMounts(ctx context.Context) ([]GuestMount, error) {
+	if uvm.gc == nil {
+		return nil, errNotSupported
+	}
+	return uvm.gc.ListMounts(ctx)
+}
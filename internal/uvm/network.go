@@ -680,6 +680,34 @@ func (uvm *UtilityVM) AddNICInGuest(ctx context.Context, cfg *guestresource.LCOW
 	return uvm.modify(ctx, &request)
 }
 
+// AddVPCINIC assigns the virtual function identified by `deviceID` to the UVM
+// via VPCI and configures the resulting interface inside the lcow guest,
+// giving hypervisor-isolated workloads near-native network throughput. The
+// guest resolves the interface from the device's VMBus GUID; `macAddress`, if
+// set, is recorded on the adapter settings. The returned VPCIDevice releases
+// the virtual function when closed.
+func (uvm *UtilityVM) AddVPCINIC(ctx context.Context, deviceID string, macAddress string) (*VPCIDevice, error) {
+	if uvm.operatingSystem != "linux" {
+		return nil, errNotSupported
+	}
+	vpci, err := uvm.AssignDevice(ctx, deviceID, 0, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign VF NIC device %s: %w", deviceID, err)
+	}
+	cfg := &guestresource.LCOWNetworkAdapter{
+		ID:           vpci.VMBusGUID,
+		MacAddress:   macAddress,
+		VPCIAssigned: true,
+	}
+	if err := uvm.AddNICInGuest(ctx, cfg); err != nil {
+		if releaseErr := vpci.Release(ctx); releaseErr != nil {
+			log.G(ctx).WithError(releaseErr).Warn("failed to release VF NIC device on rollback")
+		}
+		return nil, fmt.Errorf("failed to configure VF NIC %s in guest: %w", deviceID, err)
+	}
+	return vpci, nil
+}
+
 // RemoveNICInGuest makes a request to remove a network interface inside the lcow guest.
 // This is primarily used for removing NICs in the guest that were VPCI assigned.
 func (uvm *UtilityVM) RemoveNICInGuest(ctx context.Context, cfg *guestresource.LCOWNetworkAdapter) error {
@@ -0,0 +1,70 @@
+//go:build windows
+
+package uvm
+
+import (
+	"context"
+
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	hcsschema "github.com/Microsoft/hcsshim/internal/hcs/schema2"
+)
+
+// numaTopology builds the NUMA section of the compute topology document from
+// the create options, or returns nil if the caller didn't constrain placement.
+func (opts *Options) numaTopology() *hcsschema.Numa {
+	if len(opts.PreferredPhysicalNumaNodes) == 0 && len(opts.NumaSettings) == 0 {
+		return nil
+	}
+	numa := &hcsschema.Numa{
+		PreferredPhysicalNodes: opts.PreferredPhysicalNumaNodes,
+		Settings:               opts.NumaSettings,
+	}
+	if len(opts.NumaSettings) > 0 {
+		numa.VirtualNodeCount = uint8(len(opts.NumaSettings))
+	}
+	return numa
+}
+
+// NumaNode describes a single virtual NUMA node of a utility VM and how it
+// maps onto the host.
+type NumaNode struct {
+	VirtualNodeIndex      int32
+	PhysicalNodeNumber    int32
+	VirtualProcessorCount int32
+	MemoryUsageInPages    int32
+}
+
+// NumaTopology is the virtual NUMA node layout of a utility VM.
+type NumaTopology struct {
+	VirtualNodeCount uint32
+	Nodes            []NumaNode
+}
+
+// NumaTopology returns the UVM's virtual NUMA node layout, so that in-guest
+// NUMA pinning decisions can align with the actual VM topology. Returns
+// errNotSupported on platforms that don't expose the topology.
+func (uvm *UtilityVM) NumaTopology(ctx context.Context) (*NumaTopology, error) {
+	props, err := uvm.hcsSystem.PropertiesV2(ctx, hcsschema.PTMemory)
+	if err != nil {
+		if hcs.IsNotSupported(err) {
+			return nil, errNotSupported
+		}
+		return nil, err
+	}
+	if props.Memory == nil || len(props.Memory.VirtualNodes) == 0 {
+		return nil, errNotSupported
+	}
+
+	topology := &NumaTopology{
+		VirtualNodeCount: props.Memory.VirtualNodeCount,
+	}
+	for _, node := range props.Memory.VirtualNodes {
+		topology.Nodes = append(topology.Nodes, NumaNode{
+			VirtualNodeIndex:      node.VirtualNodeIndex,
+			PhysicalNodeNumber:    node.PhysicalNodeNumber,
+			VirtualProcessorCount: node.VirtualProcessorCount,
+			MemoryUsageInPages:    node.MemoryUsageInPages,
+		})
+	}
+	return topology, nil
+}
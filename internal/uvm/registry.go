@@ -0,0 +1,38 @@
+//go:build windows
+
+package uvm
+
+import (
+	"context"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/hcs/schema2"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestresource"
+)
+
+// ApplyRegistryChanges pushes registry value additions and key deletions into
+// the utility VM. Registry changes known up front belong in the create
+// options (see AdditionalRegistryKeys); this is for per-container
+// configuration, such as proxies or timezone, that is only known after the
+// VM is created and must be applied before the container starts.
+//
+// The RegistryChanges resource type requires a matching change in the Windows
+// guest GCS that has not shipped; current guests fail the request as an
+// unknown resource type. Until guest support exists, registry changes must be
+// supplied at creation time via RegistryChanges in the compute system
+// document, which the platform applies itself.
+func (uvm *UtilityVM) ApplyRegistryChanges(ctx context.Context, changes *hcsschema.RegistryChanges) error {
+	if uvm.operatingSystem != "windows" {
+		return errNotSupported
+	}
+	if changes == nil || (len(changes.AddValues) == 0 && len(changes.DeleteKeys) == 0) {
+		return nil
+	}
+	return uvm.modify(ctx, &hcsschema.ModifySettingRequest{
+		GuestRequest: guestrequest.ModificationRequest{
+			RequestType:  guestrequest.RequestTypeAdd,
+			ResourceType: guestresource.ResourceTypeRegistryChanges,
+			Settings:     changes,
+		},
+	})
+}
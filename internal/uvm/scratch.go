@@ -0,0 +1,44 @@
+//go:build windows
+
+package uvm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Microsoft/hcsshim/internal/uvm/scsi"
+)
+
+// RegisterScratchMount records the SCSI mount backing a container's scratch
+// disk, so that it can later be looked up by container ID. It is called by the
+// layer mounting code once the scratch disk has been attached.
+func (uvm *UtilityVM) RegisterScratchMount(containerID string, mount *scsi.Mount) {
+	uvm.scratchMountsM.Lock()
+	defer uvm.scratchMountsM.Unlock()
+	if uvm.scratchMounts == nil {
+		uvm.scratchMounts = make(map[string]*scsi.Mount)
+	}
+	uvm.scratchMounts[containerID] = mount
+}
+
+// UnregisterScratchMount removes the scratch mount registration for a
+// container. It is called when the container's layers are released.
+func (uvm *UtilityVM) UnregisterScratchMount(containerID string) {
+	uvm.scratchMountsM.Lock()
+	defer uvm.scratchMountsM.Unlock()
+	delete(uvm.scratchMounts, containerID)
+}
+
+// ResizeScratch grows the scratch disk backing the given container to
+// newSizeGB, so the container's disk quota can be raised without recreating
+// it. The backing virtual disk is expanded on the host and the guest then
+// grows the filesystem online. Scratch disks can only be grown, not shrunk.
+func (uvm *UtilityVM) ResizeScratch(ctx context.Context, containerID string, newSizeGB uint64) error {
+	uvm.scratchMountsM.Lock()
+	mount := uvm.scratchMounts[containerID]
+	uvm.scratchMountsM.Unlock()
+	if mount == nil {
+		return fmt.Errorf("no scratch mount registered for container %s", containerID)
+	}
+	return mount.Expand(ctx, newSizeGB*1024*1024*1024)
+}
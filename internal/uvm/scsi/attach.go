@@ -7,18 +7,25 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Microsoft/hcsshim/internal/log"
+	"github.com/Microsoft/hcsshim/internal/security"
 )
 
 type attachManager struct {
 	m                    sync.Mutex
 	attacher             attacher
 	unplugger            unplugger
+	trimmer              trimmer
+	expander             expander
 	numControllers       int
 	numLUNsPerController int
 	slots                [][]*attachment
 }
 
-func newAttachManager(attacher attacher, unplugger unplugger, numControllers, numLUNsPerController int, reservedSlots []Slot) *attachManager {
+func newAttachManager(attacher attacher, unplugger unplugger, trimmer trimmer, expander expander, numControllers, numLUNsPerController int, reservedSlots []Slot) *attachManager {
 	slots := make([][]*attachment, numControllers)
 	for i := range slots {
 		slots[i] = make([]*attachment, numLUNsPerController)
@@ -33,6 +40,8 @@ func newAttachManager(attacher attacher, unplugger unplugger, numControllers, nu
 	return &attachManager{
 		attacher:             attacher,
 		unplugger:            unplugger,
+		trimmer:              trimmer,
+		expander:             expander,
 		numControllers:       numControllers,
 		numLUNsPerController: numLUNsPerController,
 		slots:                slots,
@@ -46,6 +55,9 @@ type attachment struct {
 	waitErr    error
 	waitCh     chan struct{}
 	refCount   uint
+	// trimOnDetach requests a guest-side flush and trim of the device before
+	// the final detach, so the backing VHD can be compacted or safely reused.
+	trimOnDetach bool
 }
 
 type attachConfig struct {
@@ -83,6 +95,14 @@ func (am *attachManager) attach(ctx context.Context, c *attachConfig) (controlle
 		close(att.waitCh)
 	}()
 
+	if att.config.typ == "VirtualDisk" {
+		// The VM group needs access to the backing file; disks created by a
+		// different principal otherwise fail to attach with ACCESS_DENIED.
+		if err := security.GrantVmGroupAccess(att.config.path); err != nil {
+			return 0, 0, fmt.Errorf("granting VM group access to %s: %w", att.config.path, err)
+		}
+	}
+
 	if err := am.attacher.attach(ctx, att.controller, att.lun, att.config); err != nil {
 		return 0, 0, fmt.Errorf("attach %s/%s at controller %d lun %d: %w", att.config.typ, att.config.path, att.controller, att.lun, err)
 	}
@@ -103,6 +123,17 @@ func (am *attachManager) detach(ctx context.Context, controller, lun uint) (bool
 		return false, nil
 	}
 
+	if att.trimOnDetach {
+		// Trim is best-effort space reclamation; a failure must not abort the
+		// detach, as the refcount has already dropped to zero and a retry
+		// would underflow it and leak the slot.
+		if err := am.trimmer.trim(ctx, controller, lun); err != nil {
+			log.G(ctx).WithError(err).WithFields(logrus.Fields{
+				"controller": controller,
+				"lun":        lun,
+			}).Warn("failed to trim SCSI device before detach")
+		}
+	}
 	if err := am.unplugger.unplug(ctx, controller, lun); err != nil {
 		return false, fmt.Errorf("unplug controller %d lun %d: %w", controller, lun, err)
 	}
@@ -156,6 +187,57 @@ func (am *attachManager) trackAttachment(c *attachConfig) (*attachment, bool, er
 	return attachment, false, nil
 }
 
+// expand grows the virtual disk backing the attachment at controller/lun to
+// newSizeInBytes, then has the guest grow the filesystem on the device to
+// fill the new capacity.
+func (am *attachManager) expand(ctx context.Context, controller, lun uint, newSizeInBytes uint64) error {
+	am.m.Lock()
+	defer am.m.Unlock()
+
+	if controller >= uint(am.numControllers) || lun >= uint(am.numLUNsPerController) {
+		return fmt.Errorf("controller %d lun %d out of range", controller, lun)
+	}
+	att := am.slots[controller][lun]
+	if att == nil || att.config == nil {
+		return fmt.Errorf("no attachment at controller %d lun %d", controller, lun)
+	}
+	if att.config.typ != "VirtualDisk" {
+		return fmt.Errorf("attachment type %s at controller %d lun %d cannot be expanded", att.config.typ, controller, lun)
+	}
+	if att.config.readOnly {
+		return fmt.Errorf("read-only attachment at controller %d lun %d cannot be expanded", controller, lun)
+	}
+
+	// Confirm the guest can grow the filesystem before the backing disk is
+	// touched; growing the host disk cannot be undone, and doing so without
+	// a guest resize would leave the disk and filesystem out of sync.
+	if err := am.expander.expandSupported(); err != nil {
+		return fmt.Errorf("expand controller %d lun %d: %w", controller, lun, err)
+	}
+	if err := expandVirtualDisk(att.config.path, newSizeInBytes); err != nil {
+		return fmt.Errorf("expand virtual disk %s: %w", att.config.path, err)
+	}
+	if err := am.expander.expand(ctx, controller, lun); err != nil {
+		return fmt.Errorf("expand filesystem controller %d lun %d: %w", controller, lun, err)
+	}
+	return nil
+}
+
+func (am *attachManager) setTrimOnDetach(controller, lun uint, trim bool) error {
+	am.m.Lock()
+	defer am.m.Unlock()
+
+	if controller >= uint(am.numControllers) || lun >= uint(am.numLUNsPerController) {
+		return fmt.Errorf("controller %d lun %d out of range", controller, lun)
+	}
+	att := am.slots[controller][lun]
+	if att == nil {
+		return fmt.Errorf("no attachment at controller %d lun %d", controller, lun)
+	}
+	att.trimOnDetach = trim
+	return nil
+}
+
 // Caller must be holding am.m.
 func (am *attachManager) untrackAttachment(attachment *attachment) {
 	am.slots[attachment.controller][attachment.lun] = nil
@@ -29,6 +29,8 @@ type HostBackend interface {
 type GuestBackend interface {
 	mounter
 	unplugger
+	trimmer
+	expander
 }
 
 // attacher provides the low-level operations for attaching a SCSI device to a VM.
@@ -48,6 +50,35 @@ type unplugger interface {
 	unplug(ctx context.Context, controller, lun uint) error
 }
 
+// trimmer provides the low-level operation for flushing and trimming a SCSI
+// device's free blocks inside the guest OS ahead of detach.
+type trimmer interface {
+	trim(ctx context.Context, controller, lun uint) error
+}
+
+// expander provides the low-level operation for growing the filesystem on a
+// SCSI device inside the guest OS, after the backing disk has been expanded
+// on the host.
+type expander interface {
+	// expandSupported returns an error if the guest OS cannot grow a
+	// filesystem online. It must be checked before the backing disk is
+	// grown on the host, since that operation cannot be undone.
+	expandSupported() error
+	expand(ctx context.Context, controller, lun uint) error
+}
+
+func expandSupportedForOS(osType string) error {
+	switch osType {
+	case "linux":
+		return nil
+	case "windows":
+		// The guest has no online NTFS grow operation for scratch disks.
+		return errors.New("WCOW does not support online filesystem expansion")
+	default:
+		return fmt.Errorf("unsupported os type: %s", osType)
+	}
+}
+
 var _ attacher = &hcsHostBackend{}
 
 type hcsHostBackend struct {
@@ -83,6 +114,8 @@ func (hhb *hcsHostBackend) detach(ctx context.Context, controller, lun uint) err
 
 var _ mounter = &bridgeGuestBackend{}
 var _ unplugger = &bridgeGuestBackend{}
+var _ trimmer = &bridgeGuestBackend{}
+var _ expander = &bridgeGuestBackend{}
 
 type bridgeGuestBackend struct {
 	gc     *gcs.GuestConnection
@@ -123,8 +156,33 @@ func (bgb *bridgeGuestBackend) unplug(ctx context.Context, controller, lun uint)
 	return bgb.gc.Modify(ctx, req)
 }
 
+func (bgb *bridgeGuestBackend) trim(ctx context.Context, controller, lun uint) error {
+	req, err := trimRequest(controller, lun, bgb.osType)
+	if err != nil {
+		return err
+	}
+	if req.RequestType == "" {
+		return nil
+	}
+	return bgb.gc.Modify(ctx, req)
+}
+
+func (bgb *bridgeGuestBackend) expandSupported() error {
+	return expandSupportedForOS(bgb.osType)
+}
+
+func (bgb *bridgeGuestBackend) expand(ctx context.Context, controller, lun uint) error {
+	req, err := expandRequest(controller, lun, bgb.osType)
+	if err != nil {
+		return err
+	}
+	return bgb.gc.Modify(ctx, req)
+}
+
 var _ mounter = &hcsGuestBackend{}
 var _ unplugger = &hcsGuestBackend{}
+var _ trimmer = &hcsGuestBackend{}
+var _ expander = &hcsGuestBackend{}
 
 type hcsGuestBackend struct {
 	system *hcs.System
@@ -165,6 +223,29 @@ func (hgb *hcsGuestBackend) unplug(ctx context.Context, controller, lun uint) er
 	return hgb.system.Modify(ctx, &hcsschema.ModifySettingRequest{GuestRequest: req})
 }
 
+func (hgb *hcsGuestBackend) trim(ctx context.Context, controller, lun uint) error {
+	req, err := trimRequest(controller, lun, hgb.osType)
+	if err != nil {
+		return err
+	}
+	if req.RequestType == "" {
+		return nil
+	}
+	return hgb.system.Modify(ctx, &hcsschema.ModifySettingRequest{GuestRequest: req})
+}
+
+func (hgb *hcsGuestBackend) expandSupported() error {
+	return expandSupportedForOS(hgb.osType)
+}
+
+func (hgb *hcsGuestBackend) expand(ctx context.Context, controller, lun uint) error {
+	req, err := expandRequest(controller, lun, hgb.osType)
+	if err != nil {
+		return err
+	}
+	return hgb.system.Modify(ctx, &hcsschema.ModifySettingRequest{GuestRequest: req})
+}
+
 func mountRequest(controller, lun uint, path string, config *mountConfig, osType string) (guestrequest.ModificationRequest, error) {
 	req := guestrequest.ModificationRequest{
 		ResourceType: guestresource.ResourceTypeMappedVirtualDisk,
@@ -249,3 +330,42 @@ func unplugRequest(controller, lun uint, osType string) (guestrequest.Modificati
 	}
 	return req, nil
 }
+
+func expandRequest(controller, lun uint, osType string) (guestrequest.ModificationRequest, error) {
+	switch osType {
+	case "windows":
+		// The guest has no online NTFS grow operation for scratch disks.
+		return guestrequest.ModificationRequest{}, errors.New("WCOW does not support online filesystem expansion")
+	case "linux":
+		return guestrequest.ModificationRequest{
+			ResourceType: guestresource.ResourceTypeSCSIFilesystemResize,
+			RequestType:  guestrequest.RequestTypeUpdate,
+			Settings: guestresource.SCSIDevice{
+				Controller: uint8(controller),
+				Lun:        uint8(lun),
+			},
+		}, nil
+	default:
+		return guestrequest.ModificationRequest{}, fmt.Errorf("unsupported os type: %s", osType)
+	}
+}
+
+func trimRequest(controller, lun uint, osType string) (guestrequest.ModificationRequest, error) {
+	var req guestrequest.ModificationRequest
+	switch osType {
+	case "windows":
+		// Windows doesn't support a trim operation, so treat as no-op.
+	case "linux":
+		req = guestrequest.ModificationRequest{
+			ResourceType: guestresource.ResourceTypeSCSITrim,
+			RequestType:  guestrequest.RequestTypeUpdate,
+			Settings: guestresource.SCSIDevice{
+				Controller: uint8(controller),
+				Lun:        uint8(lun),
+			},
+		}
+	default:
+		return guestrequest.ModificationRequest{}, fmt.Errorf("unsupported os type: %s", osType)
+	}
+	return req, nil
+}
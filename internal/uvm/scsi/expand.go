@@ -0,0 +1,33 @@
+//go:build windows
+
+package scsi
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/Microsoft/go-winio/vhd"
+	"golang.org/x/sys/windows"
+
+	"github.com/Microsoft/hcsshim/internal/winapi"
+)
+
+// expandVirtualDisk grows the VHD at path to newSizeInBytes. The VHD may be
+// attached to a running VM; ResizeVirtualDisk supports online expansion and
+// the new capacity is surfaced to the VM on the next device rescan.
+func expandVirtualDisk(path string, newSizeInBytes uint64) error {
+	handle, err := vhd.OpenVirtualDisk(path, vhd.VirtualDiskAccessNone, vhd.OpenVirtualDiskFlagNone)
+	if err != nil {
+		return err
+	}
+	defer syscall.CloseHandle(handle) //nolint:errcheck
+
+	params := winapi.ResizeVirtualDiskParameters{
+		Version:  2,
+		Version2: winapi.ResizeVirtualDiskVersion2{NewSize: newSizeInBytes},
+	}
+	if err := winapi.ResizeVirtualDisk(windows.Handle(handle), 0, &params, nil); err != nil {
+		return fmt.Errorf("failed to resize virtual disk: %w", err)
+	}
+	return nil
+}
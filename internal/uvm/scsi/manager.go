@@ -56,7 +56,7 @@ func NewManager(
 	if hb == nil || gb == nil {
 		return nil, errors.New("host and guest backend must not be nil")
 	}
-	am := newAttachManager(hb, gb, numControllers, numLUNsPerController, reservedSlots)
+	am := newAttachManager(hb, gb, gb, gb, numControllers, numLUNsPerController, reservedSlots)
 	mm := newMountManager(gb, guestMountFmt)
 	return &Manager{am, mm}, nil
 }
@@ -114,6 +114,25 @@ func (m *Mount) GuestPath() string {
 	return m.guestPath
 }
 
+// SetTrimOnRelease instructs the guest to flush and trim the device before it
+// is detached, once a release removes the last reference to the attachment.
+// Off by default; useful for recycled scratch disks so one container's freed
+// blocks aren't carried into the next use of the backing VHD.
+func (m *Mount) SetTrimOnRelease(trim bool) error {
+	return m.mgr.attachManager.setTrimOnDetach(m.controller, m.lun, trim)
+}
+
+// Expand grows the virtual disk backing the SCSI attachment to newSizeInBytes,
+// then instructs the guest OS to grow the filesystem on the device to fill the
+// new capacity. The resize is performed online, so the mount remains usable
+// throughout; this allows e.g. raising a container's scratch disk quota without
+// recreating the container.
+//
+// This is only supported for read-write virtual disk attachments on LCOW.
+func (m *Mount) Expand(ctx context.Context, newSizeInBytes uint64) error {
+	return m.mgr.attachManager.expand(ctx, m.controller, m.lun, newSizeInBytes)
+}
+
 // Release releases the SCSI mount. Refcount tracking is used in case multiple instances
 // of the same attachment or mount are used. If the refcount for the guest OS mount
 // reaches 0, the guest OS mount is removed. If the refcount for the SCSI attachment
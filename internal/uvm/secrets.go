@@ -0,0 +1,43 @@
+//go:build windows
+
+package uvm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Microsoft/hcsshim/internal/protocol/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/protocol/guestresource"
+)
+
+// DeliverSecret streams `payload` to the guest over the guest connection and
+// materializes it as a file named `name` under a tmpfs mounted at
+// `mountPath` inside the UVM. The secret never touches host-visible or
+// persistent storage, unlike VSMB or Plan9 shares. Delivering a secret with
+// the same name and mount path again replaces the previous payload. Payloads
+// are subject to the same size bound as WriteGuestFile.
+func (uvm *UtilityVM) DeliverSecret(ctx context.Context, name string, payload []byte, mountPath string) error {
+	if uvm.gc == nil {
+		return errNotSupported
+	}
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid secret name %q", name)
+	}
+	if mountPath == "" {
+		return fmt.Errorf("secret mount path must be specified")
+	}
+	if len(payload) > MaxGuestFileTransferSize {
+		return fmt.Errorf("%d byte secret %s exceeds the %d byte transfer limit", len(payload), name, MaxGuestFileTransferSize)
+	}
+	req := guestrequest.ModificationRequest{
+		ResourceType: guestresource.ResourceTypeSecret,
+		RequestType:  guestrequest.RequestTypeAdd,
+		Settings: &guestresource.SecretDelivery{
+			Name:      name,
+			MountPath: mountPath,
+			Payload:   payload,
+		},
+	}
+	return uvm.GuestRequest(ctx, req)
+}
@@ -239,6 +239,13 @@ func (uvm *UtilityVM) Start(ctx context.Context) (err error) {
 		close(uvm.exitCh)
 	}()
 
+	if uvm.crashDumpDirectory != "" {
+		// The collector must outlive this call's context, like the wait above.
+		if err := uvm.startCrashDumpCollector(context.WithoutCancel(pCtx)); err != nil {
+			e.WithError(err).Warning("failed to subscribe for guest crash notifications")
+		}
+	}
+
 	// Collect any errors from writing entropy or establishing the log
 	// connection.
 	if err = g.Wait(); err != nil {
@@ -256,8 +263,16 @@ func (uvm *UtilityVM) Start(ctx context.Context) (err error) {
 		var initGuestState *gcs.InitialGuestState
 		if uvm.OS() == "windows" {
 			// Default to setting the time zone in the UVM to the hosts time zone unless the client asked to avoid this behavior. If so, assign
-			// to UTC.
-			if uvm.noInheritHostTimezone {
+			// to UTC. An explicitly requested time zone takes precedence over both.
+			if uvm.timezone != "" {
+				tz, err := getTimezoneByName(uvm.timezone)
+				if err != nil {
+					return err
+				}
+				initGuestState = &gcs.InitialGuestState{
+					Timezone: tz,
+				}
+			} else if uvm.noInheritHostTimezone {
 				initGuestState = &gcs.InitialGuestState{
 					Timezone: utcTimezone,
 				}
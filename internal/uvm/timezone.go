@@ -3,10 +3,12 @@
 package uvm
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	hcsschema "github.com/Microsoft/hcsshim/internal/hcs/schema2"
 	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
 )
 
 // UTC has everything set to 0's. Just need to fill in the pointer fields and string identifiers.
@@ -17,6 +19,59 @@ var utcTimezone = &hcsschema.TimeZoneInformation{
 	DaylightDate: &hcsschema.SystemTime{},
 }
 
+// getTimezoneByName returns the named Windows time zone (for example "Pacific Standard Time") in an
+// HCS TimeZoneInformation structure, from the host's time zone database in the registry.
+func getTimezoneByName(name string) (*hcsschema.TimeZoneInformation, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SOFTWARE\Microsoft\Windows NT\CurrentVersion\Time Zones\`+name, registry.QUERY_VALUE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find time zone %q: %w", name, err)
+	}
+	defer k.Close()
+
+	// The TZI value is the REG_TZI_FORMAT structure: Bias, StandardBias and DaylightBias
+	// (each int32), followed by the standard and daylight SYSTEMTIMEs.
+	tzi, _, err := k.GetBinaryValue("TZI")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TZI for time zone %q: %w", name, err)
+	}
+	if len(tzi) != 44 {
+		return nil, fmt.Errorf("unexpected TZI length %d for time zone %q", len(tzi), name)
+	}
+	standardName, _, err := k.GetStringValue("Std")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read standard name for time zone %q: %w", name, err)
+	}
+	daylightName, _, err := k.GetStringValue("Dlt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read daylight name for time zone %q: %w", name, err)
+	}
+
+	return &hcsschema.TimeZoneInformation{
+		Bias:         int32(binary.LittleEndian.Uint32(tzi[0:4])),
+		StandardBias: int32(binary.LittleEndian.Uint32(tzi[4:8])),
+		DaylightBias: int32(binary.LittleEndian.Uint32(tzi[8:12])),
+		StandardName: standardName,
+		StandardDate: systemTimeFromTZI(tzi[12:28]),
+		DaylightName: daylightName,
+		DaylightDate: systemTimeFromTZI(tzi[28:44]),
+	}, nil
+}
+
+// systemTimeFromTZI decodes one of the SYSTEMTIME fields of a REG_TZI_FORMAT value.
+func systemTimeFromTZI(b []byte) *hcsschema.SystemTime {
+	return &hcsschema.SystemTime{
+		Year:         int32(binary.LittleEndian.Uint16(b[0:2])),
+		Month:        int32(binary.LittleEndian.Uint16(b[2:4])),
+		DayOfWeek:    int32(binary.LittleEndian.Uint16(b[4:6])),
+		Day:          int32(binary.LittleEndian.Uint16(b[6:8])),
+		Hour:         int32(binary.LittleEndian.Uint16(b[8:10])),
+		Minute:       int32(binary.LittleEndian.Uint16(b[10:12])),
+		Second:       int32(binary.LittleEndian.Uint16(b[12:14])),
+		Milliseconds: int32(binary.LittleEndian.Uint16(b[14:16])),
+	}
+}
+
 // getTimezone returns the hosts timezone in an HCS TimeZoneInformation structure and an error if there
 // is one.
 func getTimezone() (*hcsschema.TimeZoneInformation, error) {
@@ -85,6 +85,7 @@ type UtilityVM struct {
 	vpmemMaxCount           uint32 // The max number of VPMem devices.
 	vpmemMaxSizeBytes       uint64 // The max size of the layer in bytes per vPMem device.
 	vpmemMultiMapping       bool   // Enable mapping multiple VHDs onto a single VPMem device
+	vpmemNoFallback         bool   // Disable SCSI fallback for layers when VPMem slots are exhausted
 	vpmemDevicesDefault     [MaxVPMEMCount]*vPMemInfoDefault
 	vpmemDevicesMultiMapped [MaxVPMEMCount]*vPMemInfoMulti
 
@@ -93,6 +94,11 @@ type UtilityVM struct {
 	scsiControllerCount uint32 // Number of SCSI controllers in the utility VM
 	reservedSCSISlots   []scsi.Slot
 
+	// scratchMounts maps container IDs to the SCSI mount backing the
+	// container's scratch disk, so ResizeScratch can find it later.
+	scratchMounts  map[string]*scsi.Mount
+	scratchMountsM sync.Mutex
+
 	encryptScratch bool                         // Enable scratch encryption
 	vpciDevices    map[VPCIDeviceID]*VPCIDevice // map of device instance id to vpci device
 
@@ -124,6 +130,10 @@ type UtilityVM struct {
 	// Location that container process dumps will get written too.
 	processDumpLocation string
 
+	// Directory that the UVM's state is saved into when the guest reports a
+	// crash. If empty no dump is collected.
+	crashDumpDirectory string
+
 	// The CreateOpts used to create this uvm. These can be either of type
 	// uvm.OptionsLCOW or uvm.OptionsWCOW
 	createOpts interface{}
@@ -136,12 +146,21 @@ type UtilityVM struct {
 	// for the Utility VM.
 	networkSetup NetworkSetup
 
+	// timezone is the name of an explicit Windows time zone for the UVM; empty means fall back to
+	// noInheritHostTimezone behavior.
+	timezone string
+
 	// noInheritHostTimezone specifies whether to not inherit the hosts timezone for the UVM. UTC will be set as the default instead.
 	// This only applies for WCOW.
 	noInheritHostTimezone bool
 
 	// confidentialUVMOptions hold confidential UVM specific options
 	confidentialUVMOptions *ConfidentialOptions
+
+	// graphicsConsoleEnabled tracks whether the UVM was created with a
+	// graphics console (keyboard, RDP and video monitor devices) that can
+	// later be removed with RemoveGraphicsConsole.
+	graphicsConsoleEnabled bool
 }
 
 func (uvm *UtilityVM) ScratchEncryptionEnabled() bool {
@@ -10,11 +10,13 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/Microsoft/hcsshim/internal/hcs"
 	"github.com/Microsoft/hcsshim/internal/hcs/resourcepaths"
 	hcsschema "github.com/Microsoft/hcsshim/internal/hcs/schema2"
 	"github.com/Microsoft/hcsshim/internal/log"
 	"github.com/Microsoft/hcsshim/internal/protocol/guestrequest"
 	"github.com/Microsoft/hcsshim/internal/protocol/guestresource"
+	"github.com/Microsoft/hcsshim/internal/security"
 )
 
 const (
@@ -182,6 +184,12 @@ func (uvm *UtilityVM) removeVPMemDefault(ctx context.Context, hostPath string) e
 	return nil
 }
 
+// NoVPMemFallback returns whether falling back to a SCSI attachment for
+// read-only layers is disabled when no VPMem slots are available.
+func (uvm *UtilityVM) NoVPMemFallback() bool {
+	return uvm.vpmemNoFallback
+}
+
 func (uvm *UtilityVM) AddVPMem(ctx context.Context, hostPath string) (*VPMEMMount, error) {
 	if uvm.operatingSystem != "linux" {
 		return nil, errNotSupported
@@ -190,6 +198,12 @@ func (uvm *UtilityVM) AddVPMem(ctx context.Context, hostPath string) (*VPMEMMoun
 	uvm.m.Lock()
 	defer uvm.m.Unlock()
 
+	// The VM group needs access to the backing file; VHDs created by a
+	// different principal otherwise fail to attach with ACCESS_DENIED.
+	if err := security.GrantVmGroupAccess(hostPath); err != nil {
+		return nil, errors.Wrapf(err, "failed to grant VM group access to %s", hostPath)
+	}
+
 	var (
 		guestPath string
 		err       error
@@ -205,6 +219,80 @@ func (uvm *UtilityVM) AddVPMem(ctx context.Context, hostPath string) (*VPMEMMoun
 	return &VPMEMMount{GuestPath: guestPath, uvm: uvm, hostPath: hostPath}, nil
 }
 
+// GrowVPMemCapacity raises the VPMem controller's maximum device count on a
+// running utility VM to `newMax`, letting a VM that was created conservatively
+// accept more read-only layers later. The new maximum must exceed the current
+// one and may not exceed `MaxVPMEMCount`. Returns `errNotSupported` when the
+// platform disallows changing the controller capacity at runtime.
+func (uvm *UtilityVM) GrowVPMemCapacity(ctx context.Context, newMax uint32) error {
+	if uvm.operatingSystem != "linux" {
+		return errNotSupported
+	}
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	if newMax <= uvm.vpmemMaxCount {
+		return errors.Errorf("new VPMem maximum count %d does not exceed the current maximum %d", newMax, uvm.vpmemMaxCount)
+	}
+	if newMax > MaxVPMEMCount {
+		return errors.Errorf("new VPMem maximum count %d exceeds the limit of %d devices", newMax, MaxVPMEMCount)
+	}
+
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType:  guestrequest.RequestTypeUpdate,
+		ResourcePath: resourcepaths.VPMemControllerResourcePath,
+		Settings: hcsschema.VirtualPMemController{
+			MaximumCount:     newMax,
+			MaximumSizeBytes: uvm.vpmemMaxSizeBytes,
+		},
+	}
+	if err := uvm.modify(ctx, modification); err != nil {
+		if hcs.IsNotSupported(err) {
+			return errNotSupported
+		}
+		return errors.Errorf("failed to grow VPMem capacity on utility VM %s: %s", uvm.id, err)
+	}
+
+	log.G(ctx).WithFields(logrus.Fields{
+		"previousMaxCount": uvm.vpmemMaxCount,
+		"newMaxCount":      newMax,
+	}).Debug("grew VPMem controller capacity")
+	uvm.vpmemMaxCount = newMax
+	return nil
+}
+
+// VPMemDeviceInfo reports the backing of the VPMem device at `deviceNumber`:
+// the host file path, its current size on disk, the mount reference count and
+// the guest mount path. Returns `ErrNotAttached` if no device occupies the
+// slot. Only supported when VPMem multi-mapping is disabled; with
+// multi-mapping a single device is backed by many per-VHD mappings rather
+// than a single host file.
+func (uvm *UtilityVM) VPMemDeviceInfo(deviceNumber uint32) (hostPath string, sizeBytes int64, refCount int, uvmPath string, err error) {
+	if uvm.operatingSystem != "linux" {
+		return "", 0, 0, "", errNotSupported
+	}
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	if uvm.vpmemMultiMapping {
+		return "", 0, 0, "", errNotSupported
+	}
+	if deviceNumber >= uvm.vpmemMaxCount {
+		return "", 0, 0, "", errors.Errorf("device number %d exceeds the VPMem device count %d", deviceNumber, uvm.vpmemMaxCount)
+	}
+	device := uvm.vpmemDevicesDefault[deviceNumber]
+	if device == nil {
+		return "", 0, 0, "", ErrNotAttached
+	}
+	fi, err := os.Stat(device.hostPath)
+	if err != nil {
+		return "", 0, 0, "", err
+	}
+	return device.hostPath, fi.Size(), int(device.refCount), device.uvmPath, nil
+}
+
 func (uvm *UtilityVM) RemoveVPMem(ctx context.Context, hostPath string) error {
 	if uvm.operatingSystem != "linux" {
 		return errNotSupported
@@ -0,0 +1,160 @@
+//go:build windows
+
+package vmcompute
+
+import (
+	gcontext "context"
+	"syscall"
+)
+
+// Client is the interface to the vmcompute syscall bindings used by the hcs
+// package. The real implementation, returned by NewClient, delegates to the
+// package-level functions; tests substitute fakes so compute system and
+// process code can run without Windows/HCS present, and integration tests can
+// wrap the real client to inject faults such as pending operations or
+// timeouts.
+type Client interface {
+	HcsEnumerateComputeSystems(ctx gcontext.Context, query string) (computeSystems, result string, hr error)
+	HcsCreateComputeSystem(ctx gcontext.Context, id string, configuration string, identity syscall.Handle) (computeSystem HcsSystem, result string, hr error)
+	HcsOpenComputeSystem(ctx gcontext.Context, id string) (computeSystem HcsSystem, result string, hr error)
+	HcsCloseComputeSystem(ctx gcontext.Context, computeSystem HcsSystem) (hr error)
+	HcsStartComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (result string, hr error)
+	HcsShutdownComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (result string, hr error)
+	HcsTerminateComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (result string, hr error)
+	HcsPauseComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (result string, hr error)
+	HcsResumeComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (result string, hr error)
+	HcsGetComputeSystemProperties(ctx gcontext.Context, computeSystem HcsSystem, propertyQuery string) (properties, result string, hr error)
+	HcsModifyComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, configuration string) (result string, hr error)
+	HcsModifyServiceSettings(ctx gcontext.Context, settings string) (result string, hr error)
+	HcsRegisterComputeSystemCallback(ctx gcontext.Context, computeSystem HcsSystem, callback uintptr, context uintptr) (callbackHandle HcsCallback, hr error)
+	HcsUnregisterComputeSystemCallback(ctx gcontext.Context, callbackHandle HcsCallback) (hr error)
+	HcsSaveComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (result string, hr error)
+
+	HcsCreateProcess(ctx gcontext.Context, computeSystem HcsSystem, processParameters string) (processInformation HcsProcessInformation, process HcsProcess, result string, hr error)
+	HcsOpenProcess(ctx gcontext.Context, computeSystem HcsSystem, pid uint32) (process HcsProcess, result string, hr error)
+	HcsCloseProcess(ctx gcontext.Context, process HcsProcess) (hr error)
+	HcsTerminateProcess(ctx gcontext.Context, process HcsProcess) (result string, hr error)
+	HcsSignalProcess(ctx gcontext.Context, process HcsProcess, options string) (result string, hr error)
+	HcsGetProcessInfo(ctx gcontext.Context, process HcsProcess) (processInformation HcsProcessInformation, result string, hr error)
+	HcsGetProcessProperties(ctx gcontext.Context, process HcsProcess) (processProperties, result string, hr error)
+	HcsModifyProcess(ctx gcontext.Context, process HcsProcess, settings string) (result string, hr error)
+	HcsGetServiceProperties(ctx gcontext.Context, propertyQuery string) (properties, result string, hr error)
+	HcsRegisterProcessCallback(ctx gcontext.Context, process HcsProcess, callback uintptr, context uintptr) (callbackHandle HcsCallback, hr error)
+	HcsUnregisterProcessCallback(ctx gcontext.Context, callbackHandle HcsCallback) (hr error)
+}
+
+// NewClient returns the Client backed by the real vmcompute.dll syscall
+// bindings.
+func NewClient() Client {
+	return syscallClient{}
+}
+
+// syscallClient implements Client by delegating to the package-level syscall
+// wrappers, which carry the tracing and timeout handling.
+type syscallClient struct{}
+
+var _ Client = syscallClient{}
+
+func (syscallClient) HcsEnumerateComputeSystems(ctx gcontext.Context, query string) (string, string, error) {
+	return HcsEnumerateComputeSystems(ctx, query)
+}
+
+func (syscallClient) HcsCreateComputeSystem(ctx gcontext.Context, id string, configuration string, identity syscall.Handle) (HcsSystem, string, error) {
+	return HcsCreateComputeSystem(ctx, id, configuration, identity)
+}
+
+func (syscallClient) HcsOpenComputeSystem(ctx gcontext.Context, id string) (HcsSystem, string, error) {
+	return HcsOpenComputeSystem(ctx, id)
+}
+
+func (syscallClient) HcsCloseComputeSystem(ctx gcontext.Context, computeSystem HcsSystem) error {
+	return HcsCloseComputeSystem(ctx, computeSystem)
+}
+
+func (syscallClient) HcsStartComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (string, error) {
+	return HcsStartComputeSystem(ctx, computeSystem, options)
+}
+
+func (syscallClient) HcsShutdownComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (string, error) {
+	return HcsShutdownComputeSystem(ctx, computeSystem, options)
+}
+
+func (syscallClient) HcsTerminateComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (string, error) {
+	return HcsTerminateComputeSystem(ctx, computeSystem, options)
+}
+
+func (syscallClient) HcsPauseComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (string, error) {
+	return HcsPauseComputeSystem(ctx, computeSystem, options)
+}
+
+func (syscallClient) HcsResumeComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (string, error) {
+	return HcsResumeComputeSystem(ctx, computeSystem, options)
+}
+
+func (syscallClient) HcsGetComputeSystemProperties(ctx gcontext.Context, computeSystem HcsSystem, propertyQuery string) (string, string, error) {
+	return HcsGetComputeSystemProperties(ctx, computeSystem, propertyQuery)
+}
+
+func (syscallClient) HcsModifyComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, configuration string) (string, error) {
+	return HcsModifyComputeSystem(ctx, computeSystem, configuration)
+}
+
+func (syscallClient) HcsModifyServiceSettings(ctx gcontext.Context, settings string) (string, error) {
+	return HcsModifyServiceSettings(ctx, settings)
+}
+
+func (syscallClient) HcsRegisterComputeSystemCallback(ctx gcontext.Context, computeSystem HcsSystem, callback uintptr, context uintptr) (HcsCallback, error) {
+	return HcsRegisterComputeSystemCallback(ctx, computeSystem, callback, context)
+}
+
+func (syscallClient) HcsUnregisterComputeSystemCallback(ctx gcontext.Context, callbackHandle HcsCallback) error {
+	return HcsUnregisterComputeSystemCallback(ctx, callbackHandle)
+}
+
+func (syscallClient) HcsSaveComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options string) (string, error) {
+	return HcsSaveComputeSystem(ctx, computeSystem, options)
+}
+
+func (syscallClient) HcsCreateProcess(ctx gcontext.Context, computeSystem HcsSystem, processParameters string) (HcsProcessInformation, HcsProcess, string, error) {
+	return HcsCreateProcess(ctx, computeSystem, processParameters)
+}
+
+func (syscallClient) HcsOpenProcess(ctx gcontext.Context, computeSystem HcsSystem, pid uint32) (HcsProcess, string, error) {
+	return HcsOpenProcess(ctx, computeSystem, pid)
+}
+
+func (syscallClient) HcsCloseProcess(ctx gcontext.Context, process HcsProcess) error {
+	return HcsCloseProcess(ctx, process)
+}
+
+func (syscallClient) HcsTerminateProcess(ctx gcontext.Context, process HcsProcess) (string, error) {
+	return HcsTerminateProcess(ctx, process)
+}
+
+func (syscallClient) HcsSignalProcess(ctx gcontext.Context, process HcsProcess, options string) (string, error) {
+	return HcsSignalProcess(ctx, process, options)
+}
+
+func (syscallClient) HcsGetProcessInfo(ctx gcontext.Context, process HcsProcess) (HcsProcessInformation, string, error) {
+	return HcsGetProcessInfo(ctx, process)
+}
+
+func (syscallClient) HcsGetProcessProperties(ctx gcontext.Context, process HcsProcess) (string, string, error) {
+	return HcsGetProcessProperties(ctx, process)
+}
+
+func (syscallClient) HcsModifyProcess(ctx gcontext.Context, process HcsProcess, settings string) (string, error) {
+	return HcsModifyProcess(ctx, process, settings)
+}
+
+func (syscallClient) HcsGetServiceProperties(ctx gcontext.Context, propertyQuery string) (string, string, error) {
+	return HcsGetServiceProperties(ctx, propertyQuery)
+}
+
+func (syscallClient) HcsRegisterProcessCallback(ctx gcontext.Context, process HcsProcess, callback uintptr, context uintptr) (HcsCallback, error) {
+	return HcsRegisterProcessCallback(ctx, process, callback, context)
+}
+
+func (syscallClient) HcsUnregisterProcessCallback(ctx gcontext.Context, callbackHandle HcsCallback) error {
+	return HcsUnregisterProcessCallback(ctx, callbackHandle)
+}
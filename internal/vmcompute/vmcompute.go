@@ -4,6 +4,7 @@ package vmcompute
 
 import (
 	gcontext "context"
+	"runtime"
 	"syscall"
 	"time"
 
@@ -75,7 +76,7 @@ type HcsProcessInformation struct {
 	StdError syscall.Handle
 }
 
-func execute(ctx gcontext.Context, timeout time.Duration, f func() error) error {
+func execute(ctx gcontext.Context, operation string, timeout time.Duration, f func() error) error {
 	now := time.Now()
 	if timeout > 0 {
 		var cancel gcontext.CancelFunc
@@ -98,17 +99,28 @@ func execute(ctx gcontext.Context, timeout time.Duration, f func() error) error
 		}).Trace("Executing syscall with deadline")
 	}
 
+	// Track the call so that, if it never returns, it shows up in
+	// OrphanedCalls rather than silently leaking a goroutine.
+	id := trackCall(operation)
 	done := make(chan error, 1)
 	go func() {
-		done <- f()
+		err := f()
+		untrackCall(id)
+		done <- err
 	}()
 	select {
 	case <-ctx.Done():
 		if ctx.Err() == gcontext.DeadlineExceeded { //nolint:errorlint
-			log.G(ctx).WithField(logfields.Timeout, trueTimeout).
-				Warning("Syscall did not complete within operation timeout. This may indicate a platform issue. " +
-					"If it appears to be making no forward progress, obtain the stacks and see if there is a syscall " +
-					"stuck in the platform API for a significant length of time.")
+			orphanCall(id)
+			stack := make([]byte, 4096)
+			stack = stack[:runtime.Stack(stack, false)]
+			log.G(ctx).WithFields(logrus.Fields{
+				logfields.Timeout: trueTimeout,
+				"operation":       operation,
+				"stack":           string(stack),
+			}).Warning("Syscall did not complete within operation timeout. This may indicate a platform issue. " +
+				"If it appears to be making no forward progress, obtain the stacks and see if there is a syscall " +
+				"stuck in the platform API for a significant length of time.")
 		}
 		return ctx.Err()
 	case err := <-done:
@@ -127,7 +139,7 @@ func HcsEnumerateComputeSystems(ctx gcontext.Context, query string) (computeSyst
 	}()
 	span.AddAttributes(trace.StringAttribute("query", query))
 
-	return computeSystems, result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return computeSystems, result, execute(ctx, "HcsEnumerateComputeSystems", timeout.SyscallWatcher, func() error {
 		var (
 			computeSystemsp *uint16
 			resultp         *uint16
@@ -156,9 +168,9 @@ func HcsCreateComputeSystem(ctx gcontext.Context, id string, configuration strin
 	}()
 	span.AddAttributes(
 		trace.StringAttribute("id", id),
-		trace.StringAttribute("configuration", configuration))
+		trace.StringAttribute("configuration", log.ScrubComputeSystemDocument(configuration)))
 
-	return computeSystem, result, execute(ctx, timeout.SystemCreate, func() error {
+	return computeSystem, result, execute(ctx, "HcsCreateComputeSystem", timeout.SystemCreate, func() error {
 		var resultp *uint16
 		err := hcsCreateComputeSystem(id, configuration, identity, &computeSystem, &resultp)
 		if resultp != nil {
@@ -178,7 +190,7 @@ func HcsOpenComputeSystem(ctx gcontext.Context, id string) (computeSystem HcsSys
 		oc.SetSpanStatus(span, hr)
 	}()
 
-	return computeSystem, result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return computeSystem, result, execute(ctx, "HcsOpenComputeSystem", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsOpenComputeSystem(id, &computeSystem, &resultp)
 		if resultp != nil {
@@ -193,7 +205,7 @@ func HcsCloseComputeSystem(ctx gcontext.Context, computeSystem HcsSystem) (hr er
 	defer span.End()
 	defer func() { oc.SetSpanStatus(span, hr) }()
 
-	return execute(ctx, timeout.SyscallWatcher, func() error {
+	return execute(ctx, "HcsCloseComputeSystem", timeout.SyscallWatcher, func() error {
 		return hcsCloseComputeSystem(computeSystem)
 	})
 }
@@ -211,7 +223,7 @@ func HcsStartComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, option
 	}()
 	span.AddAttributes(trace.StringAttribute("options", options))
 
-	return result, execute(ctx, timeout.SystemStart, func() error {
+	return result, execute(ctx, "HcsStartComputeSystem", timeout.SystemStart, func() error {
 		var resultp *uint16
 		err := hcsStartComputeSystem(computeSystem, options, &resultp)
 		if resultp != nil {
@@ -234,7 +246,7 @@ func HcsShutdownComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, opt
 	}()
 	span.AddAttributes(trace.StringAttribute("options", options))
 
-	return result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return result, execute(ctx, "HcsShutdownComputeSystem", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsShutdownComputeSystem(computeSystem, options, &resultp)
 		if resultp != nil {
@@ -257,7 +269,7 @@ func HcsTerminateComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, op
 	}()
 	span.AddAttributes(trace.StringAttribute("options", options))
 
-	return result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return result, execute(ctx, "HcsTerminateComputeSystem", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsTerminateComputeSystem(computeSystem, options, &resultp)
 		if resultp != nil {
@@ -280,7 +292,7 @@ func HcsPauseComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, option
 	}()
 	span.AddAttributes(trace.StringAttribute("options", options))
 
-	return result, execute(ctx, timeout.SystemPause, func() error {
+	return result, execute(ctx, "HcsPauseComputeSystem", timeout.SystemPause, func() error {
 		var resultp *uint16
 		err := hcsPauseComputeSystem(computeSystem, options, &resultp)
 		if resultp != nil {
@@ -303,7 +315,7 @@ func HcsResumeComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, optio
 	}()
 	span.AddAttributes(trace.StringAttribute("options", options))
 
-	return result, execute(ctx, timeout.SystemResume, func() error {
+	return result, execute(ctx, "HcsResumeComputeSystem", timeout.SystemResume, func() error {
 		var resultp *uint16
 		err := hcsResumeComputeSystem(computeSystem, options, &resultp)
 		if resultp != nil {
@@ -324,7 +336,7 @@ func HcsGetComputeSystemProperties(ctx gcontext.Context, computeSystem HcsSystem
 	}()
 	span.AddAttributes(trace.StringAttribute("propertyQuery", propertyQuery))
 
-	return properties, result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return properties, result, execute(ctx, "HcsGetComputeSystemProperties", timeout.SyscallWatcher, func() error {
 		var (
 			propertiesp *uint16
 			resultp     *uint16
@@ -349,9 +361,9 @@ func HcsModifyComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, confi
 		}
 		oc.SetSpanStatus(span, hr)
 	}()
-	span.AddAttributes(trace.StringAttribute("configuration", configuration))
+	span.AddAttributes(trace.StringAttribute("configuration", log.ScrubComputeSystemDocument(configuration)))
 
-	return result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return result, execute(ctx, "HcsModifyComputeSystem", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsModifyComputeSystem(computeSystem, configuration, &resultp)
 		if resultp != nil {
@@ -372,7 +384,7 @@ func HcsModifyServiceSettings(ctx gcontext.Context, settings string) (result str
 	}()
 	span.AddAttributes(trace.StringAttribute("settings", settings))
 
-	return result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return result, execute(ctx, "HcsModifyServiceSettings", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsModifyServiceSettings(settings, &resultp)
 		if resultp != nil {
@@ -387,7 +399,7 @@ func HcsRegisterComputeSystemCallback(ctx gcontext.Context, computeSystem HcsSys
 	defer span.End()
 	defer func() { oc.SetSpanStatus(span, hr) }()
 
-	return callbackHandle, execute(ctx, timeout.SyscallWatcher, func() error {
+	return callbackHandle, execute(ctx, "HcsRegisterComputeSystemCallback", timeout.SyscallWatcher, func() error {
 		return hcsRegisterComputeSystemCallback(computeSystem, callback, context, &callbackHandle)
 	})
 }
@@ -397,7 +409,7 @@ func HcsUnregisterComputeSystemCallback(ctx gcontext.Context, callbackHandle Hcs
 	defer span.End()
 	defer func() { oc.SetSpanStatus(span, hr) }()
 
-	return execute(ctx, timeout.SyscallWatcher, func() error {
+	return execute(ctx, "HcsUnregisterComputeSystemCallback", timeout.SyscallWatcher, func() error {
 		return hcsUnregisterComputeSystemCallback(callbackHandle)
 	})
 }
@@ -413,12 +425,12 @@ func HcsCreateProcess(ctx gcontext.Context, computeSystem HcsSystem, processPara
 	}()
 	if span.IsRecordingEvents() {
 		// wont handle v1 process parameters
-		if s, err := log.ScrubProcessParameters(processParameters); err == nil {
+		if s, err := log.ScrubProcessParameters(ctx, processParameters); err == nil {
 			span.AddAttributes(trace.StringAttribute("processParameters", s))
 		}
 	}
 
-	return processInformation, process, result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return processInformation, process, result, execute(ctx, "HcsCreateProcess", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsCreateProcess(computeSystem, processParameters, &processInformation, &process, &resultp)
 		if resultp != nil {
@@ -439,7 +451,7 @@ func HcsOpenProcess(ctx gcontext.Context, computeSystem HcsSystem, pid uint32) (
 	}()
 	span.AddAttributes(trace.Int64Attribute("pid", int64(pid)))
 
-	return process, result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return process, result, execute(ctx, "HcsOpenProcess", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsOpenProcess(computeSystem, pid, &process, &resultp)
 		if resultp != nil {
@@ -454,7 +466,7 @@ func HcsCloseProcess(ctx gcontext.Context, process HcsProcess) (hr error) {
 	defer span.End()
 	defer func() { oc.SetSpanStatus(span, hr) }()
 
-	return execute(ctx, timeout.SyscallWatcher, func() error {
+	return execute(ctx, "HcsCloseProcess", timeout.SyscallWatcher, func() error {
 		return hcsCloseProcess(process)
 	})
 }
@@ -469,7 +481,7 @@ func HcsTerminateProcess(ctx gcontext.Context, process HcsProcess) (result strin
 		oc.SetSpanStatus(span, hr)
 	}()
 
-	return result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return result, execute(ctx, "HcsTerminateProcess", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsTerminateProcess(process, &resultp)
 		if resultp != nil {
@@ -490,7 +502,7 @@ func HcsSignalProcess(ctx gcontext.Context, process HcsProcess, options string)
 	}()
 	span.AddAttributes(trace.StringAttribute("options", options))
 
-	return result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return result, execute(ctx, "HcsSignalProcess", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsSignalProcess(process, options, &resultp)
 		if resultp != nil {
@@ -510,7 +522,7 @@ func HcsGetProcessInfo(ctx gcontext.Context, process HcsProcess) (processInforma
 		oc.SetSpanStatus(span, hr)
 	}()
 
-	return processInformation, result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return processInformation, result, execute(ctx, "HcsGetProcessInfo", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsGetProcessInfo(process, &processInformation, &resultp)
 		if resultp != nil {
@@ -530,7 +542,7 @@ func HcsGetProcessProperties(ctx gcontext.Context, process HcsProcess) (processP
 		oc.SetSpanStatus(span, hr)
 	}()
 
-	return processProperties, result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return processProperties, result, execute(ctx, "HcsGetProcessProperties", timeout.SyscallWatcher, func() error {
 		var (
 			processPropertiesp *uint16
 			resultp            *uint16
@@ -557,7 +569,7 @@ func HcsModifyProcess(ctx gcontext.Context, process HcsProcess, settings string)
 	}()
 	span.AddAttributes(trace.StringAttribute("settings", settings))
 
-	return result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return result, execute(ctx, "HcsModifyProcess", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsModifyProcess(process, settings, &resultp)
 		if resultp != nil {
@@ -578,7 +590,7 @@ func HcsGetServiceProperties(ctx gcontext.Context, propertyQuery string) (proper
 	}()
 	span.AddAttributes(trace.StringAttribute("propertyQuery", propertyQuery))
 
-	return properties, result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return properties, result, execute(ctx, "HcsGetServiceProperties", timeout.SyscallWatcher, func() error {
 		var (
 			propertiesp *uint16
 			resultp     *uint16
@@ -599,7 +611,7 @@ func HcsRegisterProcessCallback(ctx gcontext.Context, process HcsProcess, callba
 	defer span.End()
 	defer func() { oc.SetSpanStatus(span, hr) }()
 
-	return callbackHandle, execute(ctx, timeout.SyscallWatcher, func() error {
+	return callbackHandle, execute(ctx, "HcsRegisterProcessCallback", timeout.SyscallWatcher, func() error {
 		return hcsRegisterProcessCallback(process, callback, context, &callbackHandle)
 	})
 }
@@ -609,7 +621,7 @@ func HcsUnregisterProcessCallback(ctx gcontext.Context, callbackHandle HcsCallba
 	defer span.End()
 	defer func() { oc.SetSpanStatus(span, hr) }()
 
-	return execute(ctx, timeout.SyscallWatcher, func() error {
+	return execute(ctx, "HcsUnregisterProcessCallback", timeout.SyscallWatcher, func() error {
 		return hcsUnregisterProcessCallback(callbackHandle)
 	})
 }
@@ -626,7 +638,7 @@ func HcsSaveComputeSystem(ctx gcontext.Context, computeSystem HcsSystem, options
 		}
 	}()
 
-	return result, execute(ctx, timeout.SyscallWatcher, func() error {
+	return result, execute(ctx, "HcsSaveComputeSystem", timeout.SyscallWatcher, func() error {
 		var resultp *uint16
 		err := hcsSaveComputeSystem(computeSystem, options, &resultp)
 		if resultp != nil {
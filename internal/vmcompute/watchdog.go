@@ -0,0 +1,67 @@
+//go:build windows
+
+package vmcompute
+
+import (
+	"sync"
+	"time"
+)
+
+// OrphanedCall describes a vmcompute syscall that exceeded the syscall
+// watcher timeout and was abandoned by its caller. The goroutine running the
+// call remains tracked until the platform finally returns from it.
+type OrphanedCall struct {
+	Operation string
+	Start     time.Time
+}
+
+type inflightCall struct {
+	operation string
+	start     time.Time
+	orphaned  bool
+}
+
+var (
+	inflightMu    sync.Mutex
+	inflightSeq   uint64
+	inflightCalls = make(map[uint64]*inflightCall)
+)
+
+func trackCall(operation string) uint64 {
+	inflightMu.Lock()
+	defer inflightMu.Unlock()
+	inflightSeq++
+	inflightCalls[inflightSeq] = &inflightCall{operation: operation, start: time.Now()}
+	return inflightSeq
+}
+
+func orphanCall(id uint64) {
+	inflightMu.Lock()
+	defer inflightMu.Unlock()
+	if c, ok := inflightCalls[id]; ok {
+		c.orphaned = true
+	}
+}
+
+func untrackCall(id uint64) {
+	inflightMu.Lock()
+	defer inflightMu.Unlock()
+	delete(inflightCalls, id)
+}
+
+// OrphanedCalls returns the vmcompute syscalls that outlived the syscall
+// watcher timeout and are still executing in the platform. Useful when
+// diagnosing calls hung inside vmcompute.dll: a non-empty result means the
+// process is holding goroutines (and likely HCS operations) that will never
+// be given back to their callers.
+func OrphanedCalls() []OrphanedCall {
+	inflightMu.Lock()
+	defer inflightMu.Unlock()
+	var calls []OrphanedCall
+	for _, c := range inflightCalls {
+		if c.orphaned {
+			calls = append(calls, OrphanedCall{Operation: c.operation, Start: c.start})
+		}
+	}
+	return calls
+}
@@ -5,12 +5,17 @@ package wclayer
 import (
 	"context"
 	"path/filepath"
+	"sync"
 
 	"github.com/Microsoft/go-winio/pkg/guid"
 	"github.com/Microsoft/hcsshim/internal/oc"
 	"go.opencensus.io/trace"
 )
 
+// layerIDCache memoizes NameToGuid results keyed by the layer directory name.
+// The mapping is deterministic for a given name, so entries never go stale.
+var layerIDCache sync.Map
+
 // LayerID returns the layer ID of a layer on disk.
 func LayerID(ctx context.Context, path string) (_ guid.GUID, err error) {
 	title := "hcsshim::LayerID"
@@ -20,5 +25,13 @@ func LayerID(ctx context.Context, path string) (_ guid.GUID, err error) {
 	span.AddAttributes(trace.StringAttribute("path", path))
 
 	_, file := filepath.Split(path)
-	return NameToGuid(ctx, file)
+	if id, ok := layerIDCache.Load(file); ok {
+		return id.(guid.GUID), nil
+	}
+	id, err := NameToGuid(ctx, file)
+	if err != nil {
+		return guid.GUID{}, err
+	}
+	layerIDCache.Store(file, id)
+	return id, nil
 }
@@ -0,0 +1,16 @@
+//go:build windows
+
+package winapi
+
+// RESIZE_VIRTUAL_DISK_VERSION_2 payload of [ResizeVirtualDiskParameters].
+type ResizeVirtualDiskVersion2 struct {
+	NewSize uint64
+}
+
+// RESIZE_VIRTUAL_DISK_PARAMETERS passed to ResizeVirtualDisk.
+type ResizeVirtualDiskParameters struct {
+	Version  uint32 // Must always be set to 2
+	Version2 ResizeVirtualDiskVersion2
+}
+
+//sys ResizeVirtualDisk(handle windows.Handle, flags uint32, parameters *ResizeVirtualDiskParameters, overlapped *windows.Overlapped) (win32err error) = virtdisk.ResizeVirtualDisk
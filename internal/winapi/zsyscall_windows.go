@@ -46,6 +46,7 @@ var (
 	modnetapi32   = windows.NewLazySystemDLL("netapi32.dll")
 	modntdll      = windows.NewLazySystemDLL("ntdll.dll")
 	modoffreg     = windows.NewLazySystemDLL("offreg.dll")
+	modvirtdisk   = windows.NewLazySystemDLL("virtdisk.dll")
 
 	procLogonUserW                             = modadvapi32.NewProc("LogonUserW")
 	procBfSetupFilter                          = modbindfltapi.NewProc("BfSetupFilter")
@@ -103,6 +104,7 @@ var (
 	procOROpenKey                              = modoffreg.NewProc("OROpenKey")
 	procORSaveHive                             = modoffreg.NewProc("ORSaveHive")
 	procORSetValue                             = modoffreg.NewProc("ORSetValue")
+	procResizeVirtualDisk                      = modvirtdisk.NewProc("ResizeVirtualDisk")
 )
 
 func LogonUser(username *uint16, domain *uint16, password *uint16, logonType uint32, logonProvider uint32, token *windows.Token) (err error) {
@@ -784,3 +786,11 @@ func _ORSetValue(handle ORHKey, valueName *uint16, valueType uint32, data *byte,
 	}
 	return
 }
+
+func ResizeVirtualDisk(handle windows.Handle, flags uint32, parameters *ResizeVirtualDiskParameters, overlapped *windows.Overlapped) (win32err error) {
+	r0, _, _ := syscall.SyscallN(procResizeVirtualDisk.Addr(), uintptr(handle), uintptr(flags), uintptr(unsafe.Pointer(parameters)), uintptr(unsafe.Pointer(overlapped)))
+	if r0 != 0 {
+		win32err = syscall.Errno(r0)
+	}
+	return
+}